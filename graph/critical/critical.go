@@ -0,0 +1,155 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://en.wikipedia.org/wiki/Critical_path_method
+
+// Метод критического пути: по длительностям задач и топологическому порядку
+// вычисляются самое раннее и самое позднее время начала каждой задачи и резерв
+// времени. Задачи без резерва образуют критический путь - самую длинную цепочку,
+// задержка любой задачи которой сдвигает окончание всей работы.
+
+package critical
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+var (
+	ErrInvalidOrder     = errors.New("order is not topological")
+	ErrNegativeDuration = errors.New("negative duration")
+)
+
+type Duration interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Graph gives the dependencies as edges: the target of an edge starts
+// only after its source finishes.
+type Graph[K comparable] interface {
+	Edges() iter.Seq2[K, K]
+}
+
+// Sorter is a graph able to sort itself, for example dfs.Graph.
+type Sorter[K comparable] interface {
+	Graph[K]
+	TopologicalSort() ([]K, error)
+}
+
+type Task[D Duration] struct {
+	EarliestStart  D
+	EarliestFinish D
+	LatestStart    D
+	LatestFinish   D
+	// Slack is how long the task may be delayed without delaying the whole schedule.
+	Slack D
+}
+
+type Schedule[K comparable, D Duration] struct {
+	Tasks map[K]Task[D]
+	// Path is the critical path: the chain of tasks that sets Length,
+	// each task starts when its predecessor in the chain finishes.
+	Path []K
+	// Length is the finish time of the whole schedule.
+	Length D
+}
+
+// Analyze computes the schedule for the nodes of a topological order, for example kahn.Graph.Result.
+// Edges to nodes outside the order are ignored, so a result limited by break points works as is.
+func Analyze[K comparable, D Duration](order []K, g Graph[K], duration func(node K) D) (*Schedule[K, D], error) {
+	position := make(map[K]int, len(order))
+	for i, node := range order {
+		if _, ok := position[node]; ok {
+			return nil, fmt.Errorf("%w: duplicate node %v", ErrInvalidOrder, node)
+		}
+		position[node] = i
+	}
+
+	successors := make([][]int, len(order))
+	durations := make([]D, len(order))
+	for i, node := range order {
+		if durations[i] = duration(node); durations[i] < 0 {
+			return nil, fmt.Errorf("%w: %v", ErrNegativeDuration, node)
+		}
+	}
+	for from, to := range g.Edges() {
+		i, ok := position[from]
+		if !ok {
+			continue
+		}
+		j, ok := position[to]
+		if !ok {
+			continue
+		}
+		if j <= i {
+			return nil, fmt.Errorf("%w: %v goes before %v", ErrInvalidOrder, to, from)
+		}
+		successors[i] = append(successors[i], j)
+	}
+
+	tasks := make([]Task[D], len(order))
+	// prev is the predecessor that set the earliest start, it keeps the critical
+	// path exact where float slack is not an exact zero
+	prev := make([]int, len(order))
+	for i := range prev {
+		prev[i] = -1
+	}
+	var length D
+
+	for i := range order {
+		tasks[i].EarliestFinish = tasks[i].EarliestStart + durations[i]
+		length = max(length, tasks[i].EarliestFinish)
+		for _, j := range successors[i] {
+			if prev[j] < 0 || tasks[i].EarliestFinish > tasks[j].EarliestStart {
+				tasks[j].EarliestStart = tasks[i].EarliestFinish
+				prev[j] = i
+			}
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		tasks[i].LatestFinish = length
+		for _, j := range successors[i] {
+			tasks[i].LatestFinish = min(tasks[i].LatestFinish, tasks[j].LatestStart)
+		}
+		tasks[i].LatestStart = tasks[i].LatestFinish - durations[i]
+		tasks[i].Slack = tasks[i].LatestStart - tasks[i].EarliestStart
+	}
+
+	result := &Schedule[K, D]{
+		Tasks:  make(map[K]Task[D], len(order)),
+		Length: length,
+	}
+	for i, node := range order {
+		result.Tasks[node] = tasks[i]
+	}
+
+	current := -1
+	for i := range order {
+		if tasks[i].EarliestFinish == length {
+			current = i
+			break
+		}
+	}
+	for ; current >= 0; current = prev[current] {
+		result.Path = append(result.Path, order[current])
+	}
+	slices.Reverse(result.Path)
+
+	return result, nil
+}
+
+// AnalyzeGraph sorts the graph and computes the schedule for all of its nodes.
+func AnalyzeGraph[K comparable, D Duration](g Sorter[K], duration func(node K) D) (*Schedule[K, D], error) {
+	order, err := g.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(order, g, duration)
+}
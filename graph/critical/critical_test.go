@@ -0,0 +1,125 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package critical
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/algorithms/graph/dfs"
+	"go.osspkg.com/algorithms/graph/kahn"
+)
+
+var (
+	testDurations = map[string]time.Duration{
+		"fetch":   2 * time.Second,
+		"deps":    5 * time.Second,
+		"codegen": 1 * time.Second,
+		"compile": 4 * time.Second,
+		"lint":    3 * time.Second,
+		"test":    6 * time.Second,
+		"package": 1 * time.Second,
+	}
+	testEdges = [][2]string{
+		{"fetch", "deps"}, {"fetch", "codegen"}, {"fetch", "lint"},
+		{"deps", "compile"}, {"codegen", "compile"},
+		{"compile", "test"}, {"lint", "package"}, {"test", "package"},
+	}
+)
+
+func duration(node string) time.Duration {
+	return testDurations[node]
+}
+
+func TestUnit_AnalyzeGraph(t *testing.T) {
+	g := dfs.NewGraph[string]()
+	for _, node := range []string{"fetch", "deps", "codegen", "compile", "lint", "test", "package"} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	for _, edge := range testEdges {
+		casecheck.NoError(t, g.AddEdge(edge[0], edge[1]))
+	}
+
+	s, err := AnalyzeGraph[string](g, duration)
+	casecheck.NoError(t, err)
+
+	casecheck.Equal(t, 18*time.Second, s.Length)
+	casecheck.Equal(t, []string{"fetch", "deps", "compile", "test", "package"}, s.Path)
+
+	casecheck.Equal(t, Task[time.Duration]{
+		EarliestStart:  2 * time.Second,
+		EarliestFinish: 3 * time.Second,
+		LatestStart:    6 * time.Second,
+		LatestFinish:   7 * time.Second,
+		Slack:          4 * time.Second,
+	}, s.Tasks["codegen"])
+	casecheck.Equal(t, 12*time.Second, s.Tasks["lint"].Slack)
+	casecheck.Equal(t, time.Duration(0), s.Tasks["test"].Slack)
+	casecheck.Equal(t, 17*time.Second, s.Tasks["package"].LatestStart)
+
+	casecheck.NoError(t, g.AddEdge("package", "fetch"))
+	_, err = AnalyzeGraph[string](g, duration)
+	casecheck.True(t, errors.Is(err, dfs.ErrCycleDetected))
+}
+
+func TestUnit_Analyze_Kahn(t *testing.T) {
+	g := kahn.New[string]()
+	for _, edge := range testEdges {
+		casecheck.NoError(t, g.Add(edge[0], edge[1]))
+	}
+
+	g.BreakPoint("compile")
+	casecheck.NoError(t, g.Build())
+
+	s, err := Analyze(g.Result(), g, func(node string) int {
+		return int(testDurations[node] / time.Second)
+	})
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 11, s.Length)
+	casecheck.Equal(t, []string{"fetch", "deps", "compile"}, s.Path)
+	casecheck.Equal(t, 4, s.Tasks["codegen"].Slack)
+	casecheck.Equal(t, 4, len(s.Tasks))
+}
+
+func TestUnit_Analyze_Float(t *testing.T) {
+	g := kahn.New[string]()
+	casecheck.NoError(t, g.Add("a", "b"))
+	casecheck.NoError(t, g.Add("b", "c"))
+	casecheck.NoError(t, g.Add("a", "d"))
+
+	durations := map[string]float64{"a": 0.1, "b": 0.2, "c": 0.3, "d": 0.4}
+	s, err := Analyze([]string{"a", "b", "d", "c"}, g, func(node string) float64 {
+		return durations[node]
+	})
+	casecheck.NoError(t, err)
+
+	// the float sum is not exactly 0.6 and the slack of the chain is not an exact zero
+	casecheck.True(t, math.Abs(s.Length-0.6) < 1e-9, "length %v", s.Length)
+	casecheck.Equal(t, []string{"a", "b", "c"}, s.Path)
+}
+
+func TestUnit_Analyze_Errors(t *testing.T) {
+	g := kahn.New[string]()
+	casecheck.NoError(t, g.Add("a", "b"))
+
+	_, err := Analyze([]string{"b", "a"}, g, func(string) int { return 1 })
+	casecheck.True(t, errors.Is(err, ErrInvalidOrder))
+
+	_, err = Analyze([]string{"a", "a", "b"}, g, func(string) int { return 1 })
+	casecheck.True(t, errors.Is(err, ErrInvalidOrder))
+
+	_, err = Analyze([]string{"a", "b"}, g, func(string) int { return -1 })
+	casecheck.True(t, errors.Is(err, ErrNegativeDuration))
+
+	s, err := Analyze([]string{}, g, func(string) int { return 1 })
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 0, s.Length)
+	casecheck.Equal(t, 0, len(s.Path))
+}
@@ -0,0 +1,160 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://en.wikipedia.org/wiki/Transitive_reduction
+
+package dfs
+
+import (
+	"iter"
+
+	"go.osspkg.com/algorithms/structs/bitmap"
+)
+
+// Reachable reports whether there is a path from one node to another,
+// a node always reaches itself.
+func (g *Graph[K]) Reachable(from, to K) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.nodes[from]; !ok {
+		return false
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return false
+	}
+	_, ok := g.shortestPath(from, to)
+	return ok
+}
+
+// Descendants iterates over the nodes reachable from the node, nearest first.
+func (g *Graph[K]) Descendants(key K) iter.Seq[K] {
+	return g.reachSeq(key, func() map[K]map[K]uint64 { return g.adjacency })
+}
+
+// Ancestors iterates over the nodes the node is reachable from, nearest first.
+func (g *Graph[K]) Ancestors(key K) iter.Seq[K] {
+	return g.reachSeq(key, func() map[K]map[K]uint64 { return g.reverse })
+}
+
+func (g *Graph[K]) reachSeq(key K, edges func() map[K]map[K]uint64) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		g.mu.RLock()
+		nodes := g.reach(key, edges())
+		g.mu.RUnlock()
+
+		for _, node := range nodes {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// reach returns nodes reachable over the edges in breadth-first order without the start node,
+// the caller must hold the lock.
+func (g *Graph[K]) reach(start K, edges map[K]map[K]uint64) []K {
+	nodes, _, _ := g.bfs(start, edges)
+	return nodes[1:]
+}
+
+// TransitiveClosure returns a new graph with an edge from every node to every node reachable from it.
+// A node gets an edge to itself only when it lies on a cycle.
+func (g *Graph[K]) TransitiveClosure() *Graph[K] {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	keys, adj := g.indexed()
+	rows := reachability(adj)
+
+	result := newGraphOf(keys)
+	for i, key := range keys {
+		for j := range rows[i].All() {
+			_ = result.AddEdge(key, keys[j]) //nolint:errcheck
+		}
+	}
+	return result
+}
+
+// TransitiveReduction returns a new graph with the fewest edges and the same reachability:
+// an edge is dropped when its target is also reachable through another successor.
+// The reduction is unique only for a DAG, so a cycle is reported as *CycleError.
+func (g *Graph[K]) TransitiveReduction() (*Graph[K], error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, err := g.topologicalSort(); err != nil {
+		return nil, err
+	}
+
+	keys, adj := g.indexed()
+	rows := reachability(adj)
+
+	result := newGraphOf(keys)
+	for i, key := range keys {
+		indirect := bitmap.New(bitmap.OptDisableLock(), bitmap.OptMaxIndex(uint64(len(keys))))
+		for _, next := range adj[i] {
+			indirect.Or(rows[next])
+		}
+		for _, next := range adj[i] {
+			if !indirect.Has(uint64(next)) {
+				_ = result.AddEdge(key, keys[next]) //nolint:errcheck
+			}
+		}
+	}
+	return result, nil
+}
+
+func newGraphOf[K comparable](keys []K) *Graph[K] {
+	result := NewGraph[K]()
+	for _, key := range keys {
+		_ = result.AddNode(key) //nolint:errcheck
+	}
+	return result
+}
+
+// reachability returns a bitmap row of reachable positions for every node. Rows are built
+// over strongly connected components in reverse topological order, so each row is
+// the union of the rows of the successors and nodes of one component share a row.
+func reachability(adj [][]int) []*bitmap.Bitmap {
+	components := tarjan(adj, 0)
+
+	owner := make([]int, len(adj))
+	for i, component := range components {
+		for _, node := range component {
+			owner[node] = i
+		}
+	}
+
+	shared := make([]*bitmap.Bitmap, len(components))
+	for i, component := range components {
+		row := bitmap.New(bitmap.OptDisableLock(), bitmap.OptMaxIndex(uint64(len(adj))))
+		cyclic := len(component) > 1
+
+		for _, node := range component {
+			for _, next := range adj[node] {
+				if owner[next] == i {
+					cyclic = true
+					continue
+				}
+				row.Set(uint64(next))
+				row.Or(shared[owner[next]])
+			}
+		}
+
+		if cyclic {
+			for _, node := range component {
+				row.Set(uint64(node))
+			}
+		}
+		shared[i] = row
+	}
+
+	rows := make([]*bitmap.Bitmap, len(adj))
+	for node := range rows {
+		rows[node] = shared[owner[node]]
+	}
+	return rows
+}
@@ -0,0 +1,81 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func collectEdges[K comparable](g *Graph[K]) [][2]K {
+	var result [][2]K
+	for from, to := range g.Edges() {
+		result = append(result, [2]K{from, to})
+	}
+	return result
+}
+
+func TestUnit_Reachable(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"a", "d"}, {"d", "c"}},
+	)
+
+	casecheck.True(t, g.Reachable("a", "c"))
+	casecheck.True(t, g.Reachable("a", "a"))
+	casecheck.False(t, g.Reachable("c", "a"))
+	casecheck.False(t, g.Reachable("a", "e"))
+	casecheck.False(t, g.Reachable("a", "z"))
+
+	casecheck.Equal(t, []string{"b", "d", "c"}, slices.Collect(g.Descendants("a")))
+	casecheck.Equal(t, []string{"b", "d", "a"}, slices.Collect(g.Ancestors("c")))
+	casecheck.Equal(t, 0, len(slices.Collect(g.Ancestors("a"))))
+
+	for node := range g.Descendants("a") {
+		casecheck.Equal(t, "b", node)
+		break
+	}
+}
+
+func TestUnit_TransitiveClosure(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "b"}, {"c", "d"}},
+	)
+
+	closure := g.TransitiveClosure()
+	casecheck.Equal(t, [][2]string{
+		{"a", "b"}, {"a", "c"}, {"a", "d"},
+		{"b", "b"}, {"b", "c"}, {"b", "d"},
+		{"c", "b"}, {"c", "c"}, {"c", "d"},
+	}, collectEdges(closure))
+	casecheck.Equal(t, 5, closure.Len())
+}
+
+func TestUnit_TransitiveReduction(t *testing.T) {
+	g := newTestGraph(
+		[]string{"app", "api", "db", "log", "cfg"},
+		[][2]string{
+			{"app", "api"}, {"app", "db"}, {"app", "log"}, {"app", "cfg"},
+			{"api", "db"}, {"api", "log"}, {"api", "cfg"},
+			{"db", "log"}, {"db", "cfg"},
+			{"log", "cfg"},
+		},
+	)
+
+	reduced, err := g.TransitiveReduction()
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, [][2]string{{"app", "api"}, {"api", "db"}, {"db", "log"}, {"log", "cfg"}}, collectEdges(reduced))
+
+	casecheck.Equal(t, collectEdges(g.TransitiveClosure()), collectEdges(reduced.TransitiveClosure()))
+
+	_ = g.AddEdge("cfg", "app") //nolint:errcheck
+	_, err = g.TransitiveReduction()
+	casecheck.True(t, errors.Is(err, ErrCycleDetected))
+}
@@ -0,0 +1,251 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://www.cs.tufts.edu/comp/150GA/homeworks/hw1/Johnson%2075.PDF
+
+package dfs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CycleError describes a cycle found in the graph. Path starts and ends
+// with the same node: a -> b -> c -> a.
+type CycleError[K comparable] struct {
+	Path []K
+}
+
+func (e *CycleError[K]) Error() string {
+	parts := make([]string, 0, len(e.Path))
+	for _, node := range e.Path {
+		parts = append(parts, fmt.Sprint(node))
+	}
+	return fmt.Sprintf("%s: %s", ErrCycleDetected.Error(), strings.Join(parts, " -> "))
+}
+
+func (e *CycleError[K]) Unwrap() error {
+	return ErrCycleDetected
+}
+
+// FindCycles returns every elementary cycle of the graph using Johnson's algorithm.
+// Each cycle has the same form as CycleError.Path and starts with its earliest added node.
+func (g *Graph[K]) FindCycles() [][]K {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	keys, adj := g.indexed()
+
+	var (
+		result  [][]K
+		blocked = make([]bool, len(keys))
+		waiting = make([]map[int]struct{}, len(keys))
+		inComp  = make([]bool, len(keys))
+	)
+
+	unblock := func(node int) {
+		queue := []int{node}
+		for len(queue) > 0 {
+			u := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+
+			blocked[u] = false
+			for w := range waiting[u] {
+				if blocked[w] {
+					queue = append(queue, w)
+				}
+			}
+			clear(waiting[u])
+		}
+	}
+
+	type frame struct {
+		node, next int
+		found      bool
+	}
+
+	// circuit is the CIRCUIT procedure of Johnson's algorithm with an explicit stack.
+	circuit := func(start int) {
+		stack := []frame{{node: start}}
+		blocked[start] = true
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			if top.next < len(adj[top.node]) {
+				neighbor := adj[top.node][top.next]
+				top.next++
+
+				switch {
+				case !inComp[neighbor]:
+				case neighbor == start:
+					cycle := make([]K, 0, len(stack)+1)
+					for _, f := range stack {
+						cycle = append(cycle, keys[f.node])
+					}
+					result = append(result, append(cycle, keys[start]))
+					top.found = true
+				case !blocked[neighbor]:
+					blocked[neighbor] = true
+					stack = append(stack, frame{node: neighbor})
+				}
+				continue
+			}
+
+			if top.found {
+				unblock(top.node)
+			} else {
+				for _, neighbor := range adj[top.node] {
+					if inComp[neighbor] {
+						waiting[neighbor][top.node] = struct{}{}
+					}
+				}
+			}
+
+			found := top.found
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 && found {
+				stack[len(stack)-1].found = true
+			}
+		}
+	}
+
+	for start := range keys {
+		component := componentOf(tarjan(adj, start), start)
+		if len(component) == 1 && !hasLoop(adj, start) {
+			continue
+		}
+
+		clear(inComp)
+		for _, node := range component {
+			inComp[node] = true
+			blocked[node] = false
+			waiting[node] = make(map[int]struct{})
+		}
+
+		circuit(start)
+	}
+
+	return result
+}
+
+func componentOf(components [][]int, node int) []int {
+	for _, component := range components {
+		for _, v := range component {
+			if v == node {
+				return component
+			}
+		}
+	}
+	return nil
+}
+
+func hasLoop(adj [][]int, node int) bool {
+	for _, neighbor := range adj[node] {
+		if neighbor == node {
+			return true
+		}
+	}
+	return false
+}
+
+// indexed returns nodes in insertion order and successors as positions in that order,
+// the caller must hold the lock.
+func (g *Graph[K]) indexed() ([]K, [][]int) {
+	keys := g.sortedNodes()
+
+	index := make(map[K]int, len(keys))
+	for i, key := range keys {
+		index[key] = i
+	}
+
+	adj := make([][]int, len(keys))
+	for i, key := range keys {
+		for _, neighbor := range g.successors(key) {
+			adj[i] = append(adj[i], index[neighbor])
+		}
+	}
+
+	return keys, adj
+}
+
+// tarjan returns strongly connected components of the subgraph of nodes with position
+// not less than from. Components come in reverse topological order.
+// see: https://en.wikipedia.org/wiki/Tarjan%27s_strongly_connected_components_algorithm
+func tarjan(adj [][]int, from int) [][]int {
+	type frame struct {
+		node, next int
+	}
+
+	var (
+		counter    int
+		index      = make([]int, len(adj))
+		low        = make([]int, len(adj))
+		onStack    = make([]bool, len(adj))
+		stack      []int
+		calls      []frame
+		components [][]int
+	)
+
+	for i := range index {
+		index[i] = -1
+	}
+
+	visit := func(node int) {
+		index[node], low[node] = counter, counter
+		counter++
+		stack = append(stack, node)
+		onStack[node] = true
+		calls = append(calls, frame{node: node})
+	}
+
+	for root := from; root < len(adj); root++ {
+		if index[root] != -1 {
+			continue
+		}
+		visit(root)
+
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			node := top.node
+
+			if top.next < len(adj[node]) {
+				neighbor := adj[node][top.next]
+				top.next++
+
+				switch {
+				case neighbor < from:
+				case index[neighbor] == -1:
+					visit(neighbor)
+				case onStack[neighbor]:
+					low[node] = min(low[node], index[neighbor])
+				}
+				continue
+			}
+
+			if low[node] == index[node] {
+				var component []int
+				for {
+					last := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[last] = false
+					component = append(component, last)
+					if last == node {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].node
+				low[parent] = min(low[parent], low[node])
+			}
+		}
+	}
+
+	return components
+}
@@ -0,0 +1,70 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"errors"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_CycleError(t *testing.T) {
+	g := newTestGraph(
+		[]string{"root", "a", "b", "c", "leaf"},
+		[][2]string{{"root", "a"}, {"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "leaf"}},
+	)
+
+	_, err := g.TopologicalSort()
+	casecheck.True(t, errors.Is(err, ErrCycleDetected))
+
+	var cycleErr *CycleError[string]
+	casecheck.True(t, errors.As(err, &cycleErr))
+	casecheck.Equal(t, []string{"a", "b", "c", "a"}, cycleErr.Path)
+	casecheck.Equal(t, "cycle detected: graph is not a DAG: a -> b -> c -> a", err.Error())
+
+	_, err = LexicographicalSort(g)
+	casecheck.True(t, errors.As(err, &cycleErr))
+	casecheck.Equal(t, []string{"a", "b", "c", "a"}, cycleErr.Path)
+}
+
+func TestUnit_FindCycles(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{
+			{"a", "b"}, {"b", "a"},
+			{"b", "c"}, {"c", "a"},
+			{"c", "d"}, {"d", "d"},
+			{"d", "e"},
+		},
+	)
+
+	casecheck.Equal(t, [][]string{
+		{"a", "b", "a"},
+		{"a", "b", "c", "a"},
+		{"d", "d"},
+	}, g.FindCycles())
+
+	dag := newTestGraph([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"a", "c"}})
+	casecheck.Equal(t, 0, len(dag.FindCycles()))
+}
+
+func TestUnit_FindCycles_Complete(t *testing.T) {
+	g := NewGraph[int]()
+	for i := 0; i < 5; i++ {
+		_ = g.AddNode(i) //nolint:errcheck
+	}
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			if i != j {
+				_ = g.AddEdge(i, j) //nolint:errcheck
+			}
+		}
+	}
+
+	// a complete digraph on n nodes has sum C(n,k)*(k-1)! elementary cycles
+	casecheck.Equal(t, 10+20+30+24, len(g.FindCycles()))
+}
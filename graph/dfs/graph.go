@@ -6,8 +6,11 @@
 package dfs
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"iter"
+	"slices"
 	"sync"
 )
 
@@ -15,18 +18,24 @@ var (
 	ErrCycleDetected = errors.New("cycle detected: graph is not a DAG")
 	ErrNodeNotFound  = errors.New("node not found")
 	ErrNodeKeyExist  = errors.New("node key exist")
+	ErrEdgeNotFound  = errors.New("edge not found")
 )
 
+// Graph keeps the insertion sequence of nodes and edges,
+// so every traversal is reproducible from run to run.
 type Graph[K comparable] struct {
 	mu        sync.RWMutex
-	nodes     map[K]struct{}
-	adjacency map[K]map[K]struct{}
+	seq       uint64
+	nodes     map[K]uint64
+	adjacency map[K]map[K]uint64
+	reverse   map[K]map[K]uint64
 }
 
 func NewGraph[K comparable]() *Graph[K] {
 	return &Graph[K]{
-		nodes:     make(map[K]struct{}, 10),
-		adjacency: make(map[K]map[K]struct{}, 10),
+		nodes:     make(map[K]uint64, 10),
+		adjacency: make(map[K]map[K]uint64, 10),
+		reverse:   make(map[K]map[K]uint64, 10),
 	}
 }
 
@@ -38,11 +47,34 @@ func (g *Graph[K]) AddNode(key K) error {
 		return fmt.Errorf("%w: %v", ErrNodeKeyExist, key)
 	}
 
-	g.nodes[key] = struct{}{}
-	if _, exists := g.adjacency[key]; !exists {
-		g.adjacency[key] = make(map[K]struct{})
+	g.seq++
+	g.nodes[key] = g.seq
+	g.adjacency[key] = make(map[K]uint64)
+	g.reverse[key] = make(map[K]uint64)
+
+	return nil
+}
+
+// RemoveNode deletes the node together with all incident edges.
+func (g *Graph[K]) RemoveNode(key K) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.nodes[key]; !ok {
+		return fmt.Errorf("%w: %v", ErrNodeNotFound, key)
 	}
 
+	for to := range g.adjacency[key] {
+		delete(g.reverse[to], key)
+	}
+	for from := range g.reverse[key] {
+		delete(g.adjacency[from], key)
+	}
+
+	delete(g.nodes, key)
+	delete(g.adjacency, key)
+	delete(g.reverse, key)
+
 	return nil
 }
 
@@ -57,51 +89,140 @@ func (g *Graph[K]) AddEdge(from, to K) error {
 		return fmt.Errorf("%w: %v", ErrNodeNotFound, to)
 	}
 
-	g.adjacency[from][to] = struct{}{}
+	if _, exists := g.adjacency[from][to]; !exists {
+		g.seq++
+		g.adjacency[from][to] = g.seq
+		g.reverse[to][from] = g.seq
+	}
 	return nil
 }
 
-func (g *Graph[K]) TopologicalSort() ([]K, error) {
+func (g *Graph[K]) RemoveEdge(from, to K) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.adjacency[from][to]; !exists {
+		return fmt.Errorf("%w: %v -> %v", ErrEdgeNotFound, from, to)
+	}
+
+	delete(g.adjacency[from], to)
+	delete(g.reverse[to], from)
+
+	return nil
+}
+
+func (g *Graph[K]) HasNode(key K) bool {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	visited := make(map[K]bool)
-	order := make([]K, 0, len(g.nodes))
+	_, ok := g.nodes[key]
+	return ok
+}
 
-	var dfs func(node K) error
-	dfs = func(node K) error {
-		if inProcess, exists := visited[node]; exists {
-			if inProcess {
-				return ErrCycleDetected
-			}
-			return nil
-		}
+func (g *Graph[K]) HasEdge(from, to K) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-		visited[node] = true
+	_, ok := g.adjacency[from][to]
+	return ok
+}
 
-		for neighbor := range g.adjacency[node] {
-			if err := dfs(neighbor); err != nil {
-				return err
-			}
-		}
+// Successors returns the targets of outgoing edges in edge insertion order.
+func (g *Graph[K]) Successors(key K) []K {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
 
-		visited[node] = false
-		order = append(order, node)
+	return g.successors(key)
+}
 
-		return nil
-	}
+// Predecessors returns the sources of incoming edges in edge insertion order.
+func (g *Graph[K]) Predecessors(key K) []K {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.predecessors(key)
+}
+
+func (g *Graph[K]) InDegree(key K) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return len(g.reverse[key])
+}
+
+func (g *Graph[K]) OutDegree(key K) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return len(g.adjacency[key])
+}
+
+func (g *Graph[K]) Len() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return len(g.nodes)
+}
 
-	for node := range g.nodes {
-		if _, exists := visited[node]; !exists {
-			if err := dfs(node); err != nil {
-				return nil, err
+// Nodes iterates over a snapshot of nodes in insertion order,
+// so the graph may be changed during the iteration.
+func (g *Graph[K]) Nodes() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		g.mu.RLock()
+		nodes := g.sortedNodes()
+		g.mu.RUnlock()
+
+		for _, node := range nodes {
+			if !yield(node) {
+				return
 			}
 		}
 	}
+}
 
-	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
-		order[i], order[j] = order[j], order[i]
+// Edges iterates over a snapshot of edges grouped by source node.
+func (g *Graph[K]) Edges() iter.Seq2[K, K] {
+	return func(yield func(K, K) bool) {
+		g.mu.RLock()
+		nodes := g.sortedNodes()
+		edges := make([][]K, len(nodes))
+		for i, node := range nodes {
+			edges[i] = g.successors(node)
+		}
+		g.mu.RUnlock()
+
+		for i, from := range nodes {
+			for _, to := range edges[i] {
+				if !yield(from, to) {
+					return
+				}
+			}
+		}
 	}
+}
+
+// sortedNodes returns nodes in insertion order, the caller must hold the lock.
+func (g *Graph[K]) sortedNodes() []K {
+	return sortBySeq(g.nodes)
+}
+
+// successors returns neighbors in edge insertion order, the caller must hold the lock.
+func (g *Graph[K]) successors(node K) []K {
+	return sortBySeq(g.adjacency[node])
+}
+
+// predecessors returns sources of incoming edges in edge insertion order, the caller must hold the lock.
+func (g *Graph[K]) predecessors(node K) []K {
+	return sortBySeq(g.reverse[node])
+}
 
-	return order, nil
+func sortBySeq[K comparable](in map[K]uint64) []K {
+	result := make([]K, 0, len(in))
+	for k := range in {
+		result = append(result, k)
+	}
+	slices.SortFunc(result, func(a, b K) int {
+		return cmp.Compare(in[a], in[b])
+	})
+	return result
 }
@@ -6,6 +6,8 @@
 package dfs
 
 import (
+	"errors"
+	"slices"
 	"testing"
 
 	"go.osspkg.com/casecheck"
@@ -35,3 +37,63 @@ func TestUnit_DAG(t *testing.T) {
 	_, err = dag.TopologicalSort()
 	casecheck.Error(t, err)
 }
+
+func TestUnit_Introspection(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}},
+	)
+
+	casecheck.True(t, g.HasNode("a"))
+	casecheck.False(t, g.HasNode("z"))
+	casecheck.True(t, g.HasEdge("a", "b"))
+	casecheck.False(t, g.HasEdge("b", "a"))
+
+	casecheck.Equal(t, []string{"b", "c"}, g.Successors("a"))
+	casecheck.Equal(t, []string{"b", "c"}, g.Predecessors("d"))
+	casecheck.Equal(t, 2, g.OutDegree("a"))
+	casecheck.Equal(t, 2, g.InDegree("d"))
+	casecheck.Equal(t, 0, g.InDegree("a"))
+
+	casecheck.Equal(t, []string{"a", "b", "c", "d"}, slices.Collect(g.Nodes()))
+
+	var edges [][2]string
+	for from, to := range g.Edges() {
+		edges = append(edges, [2]string{from, to})
+	}
+	casecheck.Equal(t, [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}}, edges)
+}
+
+func TestUnit_Remove(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "a"}},
+	)
+
+	_, err := g.TopologicalSort()
+	casecheck.Error(t, err)
+
+	casecheck.NoError(t, g.RemoveEdge("d", "a"))
+	casecheck.True(t, errors.Is(g.RemoveEdge("d", "a"), ErrEdgeNotFound))
+	casecheck.False(t, g.HasEdge("d", "a"))
+	casecheck.Equal(t, 0, g.InDegree("a"))
+
+	order, err := g.TopologicalSort()
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{"a", "c", "b", "d"}, order)
+
+	casecheck.NoError(t, g.RemoveNode("b"))
+	casecheck.True(t, errors.Is(g.RemoveNode("b"), ErrNodeNotFound))
+	casecheck.Equal(t, 3, g.Len())
+	casecheck.Equal(t, []string{"c"}, g.Successors("a"))
+	casecheck.Equal(t, []string{"c"}, g.Predecessors("d"))
+
+	// a removed node can be added again without stale edges
+	casecheck.NoError(t, g.AddNode("b"))
+	casecheck.Equal(t, 0, g.InDegree("b"))
+	casecheck.Equal(t, 0, g.OutDegree("b"))
+
+	order, err = g.TopologicalSort()
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{"b", "a", "c", "d"}, order)
+}
@@ -0,0 +1,58 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonNode[K any] struct {
+	Node  K   `json:"node"`
+	Edges []K `json:"edges,omitempty"`
+}
+
+// MarshalJSON encodes the graph as an adjacency list in insertion order,
+// so the same graph always gives the same output.
+func (g *Graph[K]) MarshalJSON() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	list := make([]jsonNode[K], 0, len(g.nodes))
+	for _, node := range g.sortedNodes() {
+		list = append(list, jsonNode[K]{Node: node, Edges: g.successors(node)})
+	}
+	return json.Marshal(list)
+}
+
+// UnmarshalJSON replaces the graph with the decoded adjacency list.
+// Every edge target must be listed as a node.
+func (g *Graph[K]) UnmarshalJSON(data []byte) error {
+	var list []jsonNode[K]
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("decode graph: %w", err)
+	}
+
+	tmp := NewGraph[K]()
+	for _, item := range list {
+		if err := tmp.AddNode(item.Node); err != nil {
+			return err
+		}
+	}
+	for _, item := range list {
+		for _, to := range item.Edges {
+			if err := tmp.AddEdge(item.Node, to); err != nil {
+				return err
+			}
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.seq, g.nodes, g.adjacency, g.reverse = tmp.seq, tmp.nodes, tmp.adjacency, tmp.reverse
+	return nil
+}
@@ -0,0 +1,37 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_JSON(t *testing.T) {
+	g := newTestGraph(
+		[]string{"c", "a", "b", "d"},
+		[][2]string{{"c", "b"}, {"c", "a"}, {"a", "b"}},
+	)
+
+	data, err := json.Marshal(g)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, `[{"node":"c","edges":["b","a"]},{"node":"a","edges":["b"]},{"node":"b"},{"node":"d"}]`, string(data))
+
+	restored := NewGraph[string]()
+	casecheck.NoError(t, json.Unmarshal(data, restored))
+	casecheck.Equal(t, collectEdges(g), collectEdges(restored))
+
+	again, err := json.Marshal(restored)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, string(data), string(again))
+
+	err = json.Unmarshal([]byte(`[{"node":"a","edges":["z"]}]`), restored)
+	casecheck.True(t, errors.Is(err, ErrNodeNotFound))
+	casecheck.Equal(t, 4, restored.Len())
+}
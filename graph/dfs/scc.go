@@ -0,0 +1,70 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://en.wikipedia.org/wiki/Tarjan%27s_strongly_connected_components_algorithm
+
+package dfs
+
+import "slices"
+
+// StronglyConnectedComponents returns groups of mutually reachable nodes.
+// Components are in topological order: edges between components only go
+// from earlier to later ones. Nodes of a component are in insertion order.
+func (g *Graph[K]) StronglyConnectedComponents() [][]K {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	keys, adj := g.indexed()
+	components := tarjan(adj, 0)
+	slices.Reverse(components)
+
+	return componentKeys(keys, components)
+}
+
+// Condense returns the condensation of the graph: a DAG where node i stands
+// for the component i and the components are the same as in StronglyConnectedComponents.
+func (g *Graph[K]) Condense() (*Graph[int], [][]K) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	keys, adj := g.indexed()
+	components := tarjan(adj, 0)
+	slices.Reverse(components)
+
+	owner := make([]int, len(keys))
+	dag := NewGraph[int]()
+	for i, component := range components {
+		for _, node := range component {
+			owner[node] = i
+		}
+		_ = dag.AddNode(i) //nolint:errcheck
+	}
+
+	for i, component := range components {
+		slices.Sort(component)
+		for _, node := range component {
+			for _, neighbor := range adj[node] {
+				if owner[neighbor] != i {
+					_ = dag.AddEdge(i, owner[neighbor]) //nolint:errcheck
+				}
+			}
+		}
+	}
+
+	return dag, componentKeys(keys, components)
+}
+
+func componentKeys[K comparable](keys []K, components [][]int) [][]K {
+	result := make([][]K, 0, len(components))
+	for _, component := range components {
+		slices.Sort(component)
+		group := make([]K, 0, len(component))
+		for _, node := range component {
+			group = append(group, keys[node])
+		}
+		result = append(result, group)
+	}
+	return result
+}
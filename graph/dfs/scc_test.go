@@ -0,0 +1,59 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_StronglyConnectedComponents(t *testing.T) {
+	g := newTestGraph(
+		[]string{"db", "cache", "api", "worker", "web", "cdn"},
+		[][2]string{
+			{"db", "cache"}, {"cache", "db"},
+			{"api", "db"}, {"api", "worker"}, {"worker", "api"},
+			{"web", "api"}, {"cdn", "web"},
+		},
+	)
+
+	casecheck.Equal(t, [][]string{
+		{"cdn"},
+		{"web"},
+		{"api", "worker"},
+		{"db", "cache"},
+	}, g.StronglyConnectedComponents())
+
+	dag, components := g.Condense()
+	casecheck.Equal(t, g.StronglyConnectedComponents(), components)
+	casecheck.Equal(t, 4, dag.Len())
+	casecheck.True(t, dag.HasEdge(0, 1))
+	casecheck.True(t, dag.HasEdge(1, 2))
+	casecheck.True(t, dag.HasEdge(2, 3))
+	casecheck.Equal(t, 1, dag.OutDegree(2))
+
+	order, err := dag.TopologicalSort()
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []int{0, 1, 2, 3}, order)
+}
+
+func TestUnit_StronglyConnectedComponents_DeepChain(t *testing.T) {
+	const size = 200_000
+
+	g := NewGraph[int]()
+	for i := 0; i < size; i++ {
+		_ = g.AddNode(i) //nolint:errcheck
+		if i > 0 {
+			_ = g.AddEdge(i-1, i) //nolint:errcheck
+		}
+	}
+	_ = g.AddEdge(size-1, 0) //nolint:errcheck
+
+	components := g.StronglyConnectedComponents()
+	casecheck.Equal(t, 1, len(components))
+	casecheck.Equal(t, size, len(components[0]))
+}
@@ -0,0 +1,187 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+)
+
+// TopologicalSort visits nodes and edges in insertion order,
+// so the same graph always produces the same order.
+// A cycle is reported as *CycleError.
+func (g *Graph[K]) TopologicalSort() ([]K, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.topologicalSort()
+}
+
+// topologicalSort keeps its own stack instead of recursion,
+// so long dependency chains do not grow the goroutine stack.
+func (g *Graph[K]) topologicalSort() ([]K, error) {
+	type frame struct {
+		node      K
+		neighbors []K
+		next      int
+	}
+
+	visited := make(map[K]bool)
+	order := make([]K, 0, len(g.nodes))
+	path := make([]K, 0, len(g.nodes))
+	var stack []frame
+
+	for _, root := range g.sortedNodes() {
+		if _, exists := visited[root]; exists {
+			continue
+		}
+
+		visited[root] = true
+		path = append(path, root)
+		stack = append(stack, frame{node: root, neighbors: g.successors(root)})
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			if top.next < len(top.neighbors) {
+				neighbor := top.neighbors[top.next]
+				top.next++
+
+				if inProcess, exists := visited[neighbor]; exists {
+					if inProcess {
+						return nil, &CycleError[K]{Path: cyclePath(path, neighbor)}
+					}
+					continue
+				}
+
+				visited[neighbor] = true
+				path = append(path, neighbor)
+				stack = append(stack, frame{node: neighbor, neighbors: g.successors(neighbor)})
+				continue
+			}
+
+			visited[top.node] = false
+			order = append(order, top.node)
+			path = path[:len(path)-1]
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	slices.Reverse(order)
+
+	return order, nil
+}
+
+// cyclePath cuts the cycle closed by node out of the current path.
+func cyclePath[K comparable](path []K, node K) []K {
+	for i, v := range path {
+		if v == node {
+			return append(append(make([]K, 0, len(path)-i+1), path[i:]...), node)
+		}
+	}
+	return []K{node, node}
+}
+
+// TopologicalSortFunc returns the smallest topological order according to less:
+// among all nodes whose dependencies are already placed, the least one goes first.
+func (g *Graph[K]) TopologicalSortFunc(less func(a, b K) bool) ([]K, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	inDegree := make(map[K]int, len(g.nodes))
+	for _, neighbors := range g.adjacency {
+		for neighbor := range neighbors {
+			inDegree[neighbor]++
+		}
+	}
+
+	queue := &priorityQueue[K]{less: less}
+	for node := range g.nodes {
+		if inDegree[node] == 0 {
+			queue.list = append(queue.list, node)
+		}
+	}
+	heap.Init(queue)
+
+	order := make([]K, 0, len(g.nodes))
+	for queue.Len() > 0 {
+		node := queue.list[0]
+		heap.Pop(queue)
+		order = append(order, node)
+
+		for neighbor := range g.adjacency[node] {
+			inDegree[neighbor]--
+			if inDegree[neighbor] == 0 {
+				heap.Push(queue, neighbor)
+			}
+		}
+	}
+
+	if len(order) != len(g.nodes) {
+		_, err := g.topologicalSort()
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// LexicographicalSort returns the lexicographically smallest topological order.
+func LexicographicalSort[K cmp.Ordered](g *Graph[K]) ([]K, error) {
+	return g.TopologicalSortFunc(cmp.Less[K])
+}
+
+type priorityQueue[K comparable] struct {
+	list []K
+	less func(a, b K) bool
+}
+
+func (q *priorityQueue[K]) Len() int           { return len(q.list) }
+func (q *priorityQueue[K]) Less(i, j int) bool { return q.less(q.list[i], q.list[j]) }
+func (q *priorityQueue[K]) Swap(i, j int)      { q.list[i], q.list[j] = q.list[j], q.list[i] }
+
+func (q *priorityQueue[K]) Push(x any) {
+	v, ok := x.(K)
+	if !ok {
+		panic("invalid queue item")
+	}
+	q.list = append(q.list, v)
+}
+
+func (q *priorityQueue[K]) Pop() any {
+	x := q.list[len(q.list)-1]
+	q.list = q.list[:len(q.list)-1]
+	return x
+}
+
+// Layers splits the topological order into levels: every node of a level depends
+// only on nodes of earlier levels. Nodes of a level are in insertion order.
+func (g *Graph[K]) Layers() ([][]K, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	order, err := g.topologicalSort()
+	if err != nil {
+		return nil, err
+	}
+
+	level := make(map[K]int, len(order))
+	for _, node := range order {
+		for neighbor := range g.adjacency[node] {
+			level[neighbor] = max(level[neighbor], level[node]+1)
+		}
+	}
+
+	var layers [][]K
+	for _, node := range g.sortedNodes() {
+		for len(layers) <= level[node] {
+			layers = append(layers, nil)
+		}
+		layers[level[node]] = append(layers[level[node]], node)
+	}
+
+	return layers, nil
+}
@@ -0,0 +1,76 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"errors"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func newTestGraph(nodes []string, edges [][2]string) *Graph[string] {
+	g := NewGraph[string]()
+	for _, node := range nodes {
+		_ = g.AddNode(node) //nolint:errcheck
+	}
+	for _, edge := range edges {
+		_ = g.AddEdge(edge[0], edge[1]) //nolint:errcheck
+	}
+	return g
+}
+
+func TestUnit_TopologicalSort_Deterministic(t *testing.T) {
+	nodes := []string{"e", "d", "c", "b", "a", "f"}
+	edges := [][2]string{{"e", "a"}, {"d", "a"}, {"c", "b"}, {"e", "b"}, {"f", "c"}}
+
+	want, err := newTestGraph(nodes, edges).TopologicalSort()
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{"f", "c", "d", "e", "b", "a"}, want)
+
+	for i := 0; i < 50; i++ {
+		got, err := newTestGraph(nodes, edges).TopologicalSort()
+		casecheck.NoError(t, err)
+		casecheck.Equal(t, want, got)
+	}
+}
+
+func TestUnit_TopologicalSortFunc(t *testing.T) {
+	nodes := []string{"e", "d", "c", "b", "a", "f"}
+	edges := [][2]string{{"e", "a"}, {"d", "a"}, {"c", "b"}, {"e", "b"}, {"f", "c"}}
+	g := newTestGraph(nodes, edges)
+
+	got, err := LexicographicalSort(g)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{"d", "e", "a", "f", "c", "b"}, got)
+
+	got, err = g.TopologicalSortFunc(func(a, b string) bool { return a > b })
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{"f", "e", "d", "c", "b", "a"}, got)
+
+	_ = g.AddEdge("b", "f") //nolint:errcheck
+	_, err = LexicographicalSort(g)
+	casecheck.True(t, errors.Is(err, ErrCycleDetected))
+}
+
+func TestUnit_Layers(t *testing.T) {
+	g := newTestGraph(
+		[]string{"web", "api", "db", "auth", "common", "utils"},
+		[][2]string{
+			{"common", "auth"}, {"common", "db"}, {"common", "api"},
+			{"auth", "api"}, {"db", "api"},
+			{"utils", "web"}, {"api", "web"},
+		},
+	)
+
+	layers, err := g.Layers()
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, [][]string{{"common", "utils"}, {"db", "auth"}, {"api"}, {"web"}}, layers)
+
+	_ = g.AddEdge("web", "common") //nolint:errcheck
+	_, err = g.Layers()
+	casecheck.True(t, errors.Is(err, ErrCycleDetected))
+}
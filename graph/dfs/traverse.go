@@ -0,0 +1,203 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+var ErrPathNotFound = errors.New("path not found")
+
+// Visitor receives nodes of Walk. Enter is called in pre-order, before the successors
+// of the node, Leave in post-order, after them. Either may be nil, returning false stops the walk.
+type Visitor[K comparable] struct {
+	Enter func(node K) bool
+	Leave func(node K) bool
+}
+
+// Walk runs a depth-first traversal from the start node over successors in edge insertion order.
+// Each node is visited once. The traversal is taken as a snapshot, so the visitor may change the graph.
+func (g *Graph[K]) Walk(start K, visitor Visitor[K]) error {
+	type event struct {
+		node  K
+		leave bool
+	}
+
+	type frame struct {
+		node      K
+		neighbors []K
+		next      int
+	}
+
+	g.mu.RLock()
+
+	if _, ok := g.nodes[start]; !ok {
+		g.mu.RUnlock()
+		return fmt.Errorf("%w: %v", ErrNodeNotFound, start)
+	}
+
+	events := make([]event, 0, 2*len(g.nodes))
+	visited := map[K]struct{}{start: {}}
+	stack := []frame{{node: start, neighbors: g.successors(start)}}
+	events = append(events, event{node: start})
+
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+
+		if top.next < len(top.neighbors) {
+			neighbor := top.neighbors[top.next]
+			top.next++
+
+			if _, ok := visited[neighbor]; !ok {
+				visited[neighbor] = struct{}{}
+				events = append(events, event{node: neighbor})
+				stack = append(stack, frame{node: neighbor, neighbors: g.successors(neighbor)})
+			}
+			continue
+		}
+
+		events = append(events, event{node: top.node, leave: true})
+		stack = stack[:len(stack)-1]
+	}
+
+	g.mu.RUnlock()
+
+	for _, e := range events {
+		fn := visitor.Enter
+		if e.leave {
+			fn = visitor.Leave
+		}
+		if fn != nil && !fn(e.node) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// BFS iterates over the nodes reachable from the start node in breadth-first order
+// together with their distance in edges, the start node comes first with zero.
+func (g *Graph[K]) BFS(start K) iter.Seq2[K, int] {
+	return func(yield func(K, int) bool) {
+		g.mu.RLock()
+		_, ok := g.nodes[start]
+		var (
+			nodes []K
+			depth []int
+		)
+		if ok {
+			nodes, depth, _ = g.bfs(start, g.adjacency)
+		}
+		g.mu.RUnlock()
+
+		for i, node := range nodes {
+			if !yield(node, depth[i]) {
+				return
+			}
+		}
+	}
+}
+
+// ShortestPath returns a path with the fewest edges, from and to included.
+func (g *Graph[K]) ShortestPath(from, to K) ([]K, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.nodes[from]; !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNodeNotFound, from)
+	}
+	if _, ok := g.nodes[to]; !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNodeNotFound, to)
+	}
+
+	path, ok := g.shortestPath(from, to)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v -> %v", ErrPathNotFound, from, to)
+	}
+	return path, nil
+}
+
+// WeaklyConnectedComponents returns groups of nodes connected when edge directions are ignored.
+// Components are ordered by their first added node, nodes of a component are in insertion order.
+func (g *Graph[K]) WeaklyConnectedComponents() [][]K {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var result [][]K
+	seen := make(map[K]struct{}, len(g.nodes))
+
+	for _, root := range g.sortedNodes() {
+		if _, ok := seen[root]; ok {
+			continue
+		}
+
+		seen[root] = struct{}{}
+		component := []K{root}
+		for queue := []K{root}; len(queue) > 0; {
+			node := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+
+			for _, edges := range []map[K]map[K]uint64{g.adjacency, g.reverse} {
+				for next := range edges[node] {
+					if _, ok := seen[next]; !ok {
+						seen[next] = struct{}{}
+						component = append(component, next)
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+
+		slices.SortFunc(component, func(a, b K) int {
+			return cmp.Compare(g.nodes[a], g.nodes[b])
+		})
+		result = append(result, component)
+	}
+
+	return result
+}
+
+// shortestPath returns the path with the fewest edges, the caller must hold the lock.
+func (g *Graph[K]) shortestPath(from, to K) ([]K, bool) {
+	_, _, parent := g.bfs(from, g.adjacency)
+	if _, ok := parent[to]; !ok {
+		return nil, false
+	}
+
+	path := []K{to}
+	for node := to; node != from; {
+		node = parent[node]
+		path = append(path, node)
+	}
+	slices.Reverse(path)
+
+	return path, true
+}
+
+// bfs returns nodes reachable over the edges in breadth-first order starting with the start node,
+// their depth and the parent of every node, the caller must hold the lock.
+func (g *Graph[K]) bfs(start K, edges map[K]map[K]uint64) ([]K, []int, map[K]K) {
+	parent := map[K]K{start: start}
+	nodes := []K{start}
+	depth := []int{0}
+
+	for i := 0; i < len(nodes); i++ {
+		for _, next := range sortBySeq(edges[nodes[i]]) {
+			if _, ok := parent[next]; ok {
+				continue
+			}
+			parent[next] = nodes[i]
+			nodes = append(nodes, next)
+			depth = append(depth, depth[i]+1)
+		}
+	}
+
+	return nodes, depth, parent
+}
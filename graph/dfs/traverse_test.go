@@ -0,0 +1,127 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dfs
+
+import (
+	"errors"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_Walk(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "a"}},
+	)
+
+	var pre, post []string
+	casecheck.NoError(t, g.Walk("a", Visitor[string]{
+		Enter: func(node string) bool { pre = append(pre, node); return true },
+		Leave: func(node string) bool { post = append(post, node); return true },
+	}))
+	casecheck.Equal(t, []string{"a", "b", "d", "c"}, pre)
+	casecheck.Equal(t, []string{"d", "b", "c", "a"}, post)
+
+	pre = pre[:0]
+	casecheck.NoError(t, g.Walk("a", Visitor[string]{
+		Enter: func(node string) bool {
+			pre = append(pre, node)
+			return node != "b"
+		},
+	}))
+	casecheck.Equal(t, []string{"a", "b"}, pre)
+
+	// the visitor works on a snapshot and may change the graph
+	casecheck.NoError(t, g.Walk("a", Visitor[string]{
+		Leave: func(node string) bool { return g.RemoveNode(node) == nil },
+	}))
+	casecheck.Equal(t, 1, g.Len())
+
+	casecheck.True(t, errors.Is(g.Walk("z", Visitor[string]{}), ErrNodeNotFound))
+}
+
+func TestUnit_BFS(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d", "e"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"d", "e"}},
+	)
+
+	var nodes []string
+	var depths []int
+	for node, depth := range g.BFS("a") {
+		nodes = append(nodes, node)
+		depths = append(depths, depth)
+	}
+	casecheck.Equal(t, []string{"a", "b", "c", "d", "e"}, nodes)
+	casecheck.Equal(t, []int{0, 1, 1, 2, 3}, depths)
+
+	count := 0
+	for range g.BFS("z") {
+		count++
+	}
+	casecheck.Equal(t, 0, count)
+}
+
+func TestUnit_ShortestPath(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d", "e", "f"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"a", "e"}, {"e", "d"}, {"d", "a"}},
+	)
+
+	path, err := g.ShortestPath("a", "d")
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{"a", "e", "d"}, path)
+
+	path, err = g.ShortestPath("c", "e")
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{"c", "d", "a", "e"}, path)
+
+	path, err = g.ShortestPath("b", "b")
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []string{"b"}, path)
+
+	_, err = g.ShortestPath("a", "f")
+	casecheck.True(t, errors.Is(err, ErrPathNotFound))
+	_, err = g.ShortestPath("a", "z")
+	casecheck.True(t, errors.Is(err, ErrNodeNotFound))
+}
+
+func TestUnit_WeaklyConnectedComponents(t *testing.T) {
+	g := newTestGraph(
+		[]string{"a", "b", "c", "d", "e", "f", "g"},
+		[][2]string{{"b", "a"}, {"c", "a"}, {"e", "d"}, {"f", "g"}, {"g", "e"}},
+	)
+
+	casecheck.Equal(t, [][]string{{"a", "b", "c"}, {"d", "e", "f", "g"}}, g.WeaklyConnectedComponents())
+}
+
+func TestUnit_DeepChain(t *testing.T) {
+	const size = 200_000
+
+	g := NewGraph[int]()
+	for i := 0; i < size; i++ {
+		_ = g.AddNode(i) //nolint:errcheck
+	}
+	for i := 1; i < size; i++ {
+		_ = g.AddEdge(i-1, i) //nolint:errcheck
+	}
+
+	order, err := g.TopologicalSort()
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, size, len(order))
+	casecheck.Equal(t, size-1, order[size-1])
+
+	count := 0
+	casecheck.NoError(t, g.Walk(0, Visitor[int]{Leave: func(int) bool { count++; return true }}))
+	casecheck.Equal(t, size, count)
+
+	_ = g.AddEdge(size-1, 0) //nolint:errcheck
+	_, err = g.TopologicalSort()
+	var cycleErr *CycleError[int]
+	casecheck.True(t, errors.As(err, &cycleErr))
+	casecheck.Equal(t, size+1, len(cycleErr.Path))
+}
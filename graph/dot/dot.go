@@ -0,0 +1,116 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://graphviz.org/doc/info/lang.html
+
+// Экспорт графа в формат Graphviz DOT и импорт подмножества этого формата:
+// ориентированный граф с узлами, цепочками ребер, атрибутами в квадратных
+// скобках и комментариями. Подграфы и неориентированные графы не поддерживаются.
+
+package dot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Source is a directed graph to export. Nodes and edges are written in the
+// order they are yielded, so a stable order gives a reproducible output.
+type Source[K comparable] interface {
+	Nodes() iter.Seq[K]
+	Edges() iter.Seq2[K, K]
+}
+
+type config[K comparable] struct {
+	name      string
+	labels    func(K) string
+	highlight []K
+}
+
+type Option[K comparable] func(c *config[K])
+
+func Name[K comparable](name string) Option[K] {
+	return func(c *config[K]) {
+		c.name = name
+	}
+}
+
+// Labels sets the text shown for a node, an empty label leaves the node id.
+func Labels[K comparable](fn func(node K) string) Option[K] {
+	return func(c *config[K]) {
+		c.labels = fn
+	}
+}
+
+// Highlight marks the nodes and edges of the path, for example CycleError.Path.
+func Highlight[K comparable](path []K) Option[K] {
+	return func(c *config[K]) {
+		c.highlight = path
+	}
+}
+
+// Encode writes the graph in DOT format. Node ids are produced by fmt.Sprint.
+func Encode[K comparable](w io.Writer, g Source[K], opts ...Option[K]) error {
+	c := &config[K]{name: "G"}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	onPath := make(map[K]struct{}, len(c.highlight))
+	pathEdges := make(map[[2]K]struct{}, len(c.highlight))
+	for i, node := range c.highlight {
+		onPath[node] = struct{}{}
+		if i > 0 {
+			pathEdges[[2]K{c.highlight[i-1], node}] = struct{}{}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", quote(c.name))
+
+	for node := range g.Nodes() {
+		var attrs []string
+		if c.labels != nil {
+			if label := c.labels(node); label != "" {
+				attrs = append(attrs, "label="+quote(label))
+			}
+		}
+		if _, ok := onPath[node]; ok {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(bw, "\t%s%s;\n", quote(fmt.Sprint(node)), attrList(attrs))
+	}
+
+	for from, to := range g.Edges() {
+		var attrs []string
+		if _, ok := pathEdges[[2]K{from, to}]; ok {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(bw, "\t%s -> %s%s;\n", quote(fmt.Sprint(from)), quote(fmt.Sprint(to)), attrList(attrs))
+	}
+
+	fmt.Fprintf(bw, "}\n")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write dot: %w", err)
+	}
+	return nil
+}
+
+func attrList(attrs []string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
+
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
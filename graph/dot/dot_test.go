@@ -0,0 +1,110 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dot
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/algorithms/graph/dfs"
+	"go.osspkg.com/algorithms/graph/kahn"
+)
+
+func identity(s string) (string, error) { return s, nil }
+
+func TestUnit_Encode(t *testing.T) {
+	g := dfs.NewGraph[string]()
+	for _, node := range []string{"a", "b", "c", `say "hi"`} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	casecheck.NoError(t, g.AddEdge("a", "b"))
+	casecheck.NoError(t, g.AddEdge("b", "c"))
+	casecheck.NoError(t, g.AddEdge("c", "a"))
+	casecheck.NoError(t, g.AddEdge("c", `say "hi"`))
+
+	_, err := g.TopologicalSort()
+	var cycleErr *dfs.CycleError[string]
+	casecheck.True(t, errors.As(err, &cycleErr))
+
+	buf := bytes.NewBuffer(nil)
+	casecheck.NoError(t, Encode[string](buf, g,
+		Name[string]("deps"),
+		Labels(strings.ToUpper),
+		Highlight(cycleErr.Path),
+	))
+
+	casecheck.Equal(t, `digraph "deps" {
+	"a" [label="A", color=red];
+	"b" [label="B", color=red];
+	"c" [label="C", color=red];
+	"say \"hi\"" [label="SAY \"HI\""];
+	"a" -> "b" [color=red];
+	"b" -> "c" [color=red];
+	"c" -> "a" [color=red];
+	"c" -> "say \"hi\"";
+}
+`, buf.String())
+}
+
+func TestUnit_RoundTrip_DFS(t *testing.T) {
+	g := dfs.NewGraph[int]()
+	for i := 5; i > 0; i-- {
+		casecheck.NoError(t, g.AddNode(i))
+	}
+	casecheck.NoError(t, g.AddEdge(5, 3))
+	casecheck.NoError(t, g.AddEdge(3, 1))
+	casecheck.NoError(t, g.AddEdge(5, 1))
+
+	buf := bytes.NewBuffer(nil)
+	casecheck.NoError(t, Encode[int](buf, g, Labels(func(n int) string { return "job " + strconv.Itoa(n) })))
+
+	doc, err := Decode(buf)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "G", doc.Name)
+	casecheck.Equal(t, "job 4", doc.Labels["4"])
+
+	restored := dfs.NewGraph[int]()
+	casecheck.NoError(t, Load(doc, strconv.Atoi, restored.AddNode, restored.AddEdge))
+
+	casecheck.Equal(t, slices.Collect(g.Nodes()), slices.Collect(restored.Nodes()))
+	for from, to := range g.Edges() {
+		casecheck.True(t, restored.HasEdge(from, to), "%d -> %d", from, to)
+	}
+	casecheck.Equal(t, 3, len(doc.Edges))
+}
+
+func TestUnit_RoundTrip_Kahn(t *testing.T) {
+	g := kahn.New[string]()
+	casecheck.NoError(t, g.Add("lib", "app"))
+	casecheck.NoError(t, g.Add("base", "lib"))
+	casecheck.NoError(t, g.Add("base", "app"))
+	casecheck.NoError(t, g.AddNode("docs"))
+
+	buf := bytes.NewBuffer(nil)
+	casecheck.NoError(t, Encode[string](buf, g))
+
+	doc, err := Decode(buf)
+	casecheck.NoError(t, err)
+
+	restored := kahn.New[string]()
+	casecheck.NoError(t, Load(doc, identity, restored.AddNode, restored.Add))
+
+	casecheck.NoError(t, g.Build())
+	casecheck.NoError(t, restored.Build())
+	casecheck.Equal(t, g.Result(), restored.Result())
+	casecheck.True(t, slices.Contains(restored.Result(), "docs"))
+
+	var a, b bytes.Buffer
+	casecheck.NoError(t, Encode[string](&a, g))
+	casecheck.NoError(t, Encode[string](&b, restored))
+	casecheck.Equal(t, a.String(), b.String())
+}
@@ -0,0 +1,382 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+var ErrSyntax = errors.New("invalid dot syntax")
+
+// Graph is a decoded DOT graph. Nodes are in the order of the first appearance.
+type Graph struct {
+	Name   string
+	Nodes  []string
+	Edges  [][2]string
+	Labels map[string]string
+
+	seen map[string]struct{}
+}
+
+// Decode reads a directed graph in DOT format.
+func Decode(r io.Reader) (*Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read dot: %w", err)
+	}
+
+	p := &parser{lex: lexer{src: string(data)}}
+	g := &Graph{Labels: make(map[string]string), seen: make(map[string]struct{})}
+	if err = p.parse(g); err != nil {
+		return nil, err
+	}
+	g.seen = nil
+
+	return g, nil
+}
+
+// Load passes the decoded nodes and edges to a graph, for example dfs.Graph.AddNode
+// and dfs.Graph.AddEdge or kahn.Graph.AddNode and kahn.Graph.Add. Without addNode
+// only the nodes of edges are added. Node ids are converted by parse.
+func Load[K comparable](g *Graph, parse func(id string) (K, error), addNode func(K) error, addEdge func(from, to K) error) error {
+	keys := make(map[string]K, len(g.Nodes))
+	for _, id := range g.Nodes {
+		key, err := parse(id)
+		if err != nil {
+			return fmt.Errorf("parse node %q: %w", id, err)
+		}
+		keys[id] = key
+
+		if addNode != nil {
+			if err = addNode(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if addEdge == nil {
+		return nil
+	}
+	for _, edge := range g.Edges {
+		from, ok := keys[edge[0]]
+		if !ok {
+			return fmt.Errorf("%w: unknown node %q", ErrSyntax, edge[0])
+		}
+		to, ok := keys[edge[1]]
+		if !ok {
+			return fmt.Errorf("%w: unknown node %q", ErrSyntax, edge[1])
+		}
+		if err := addEdge(from, to); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (g *Graph) addNode(id string) {
+	if _, ok := g.seen[id]; !ok {
+		g.seen[id] = struct{}{}
+		g.Nodes = append(g.Nodes, id)
+	}
+}
+
+const (
+	tokenEOF   = 0
+	tokenID    = 'i'
+	tokenArrow = '>'
+)
+
+type token struct {
+	kind   byte
+	text   string
+	quoted bool
+	pos    int
+}
+
+type parser struct {
+	lex  lexer
+	peek *token
+}
+
+func (p *parser) next() (token, error) {
+	if p.peek != nil {
+		t := *p.peek
+		p.peek = nil
+		return t, nil
+	}
+	return p.lex.next()
+}
+
+func (p *parser) lookahead() (token, error) {
+	if p.peek == nil {
+		t, err := p.lex.next()
+		if err != nil {
+			return t, err
+		}
+		p.peek = &t
+	}
+	return *p.peek, nil
+}
+
+func (p *parser) expect(kind byte) (token, error) {
+	t, err := p.next()
+	if err != nil {
+		return t, err
+	}
+	if t.kind != kind {
+		return t, p.errorf(t, "unexpected %q", t.text)
+	}
+	return t, nil
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%w: offset %d: %s", ErrSyntax, t.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parse(g *Graph) error {
+	t, err := p.expect(tokenID)
+	if err != nil {
+		return err
+	}
+	if keyword(t, "strict") {
+		if t, err = p.expect(tokenID); err != nil {
+			return err
+		}
+	}
+	if !keyword(t, "digraph") {
+		return p.errorf(t, "only digraph is supported")
+	}
+
+	if t, err = p.next(); err != nil {
+		return err
+	}
+	if t.kind == tokenID {
+		g.Name = t.text
+		if t, err = p.next(); err != nil {
+			return err
+		}
+	}
+	if t.kind != '{' {
+		return p.errorf(t, "want '{'")
+	}
+
+	for {
+		if t, err = p.next(); err != nil {
+			return err
+		}
+
+		switch {
+		case t.kind == '}':
+			if t, err = p.next(); err != nil {
+				return err
+			}
+			if t.kind != tokenEOF {
+				return p.errorf(t, "unexpected %q after graph", t.text)
+			}
+			return nil
+		case t.kind == ';':
+			continue
+		case t.kind != tokenID:
+			return p.errorf(t, "unexpected %q", t.text)
+		case keyword(t, "subgraph"):
+			return p.errorf(t, "subgraphs are not supported")
+		case keyword(t, "graph"), keyword(t, "node"), keyword(t, "edge"):
+			if _, err = p.attrs(); err != nil {
+				return err
+			}
+		default:
+			if err = p.statement(g, t); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *parser) statement(g *Graph, first token) error {
+	t, err := p.lookahead()
+	if err != nil {
+		return err
+	}
+
+	if t.kind == '=' {
+		p.peek = nil
+		_, err = p.expect(tokenID)
+		return err
+	}
+
+	chain := []string{first.text}
+	for t.kind == tokenArrow {
+		p.peek = nil
+		if t, err = p.expect(tokenID); err != nil {
+			return err
+		}
+		chain = append(chain, t.text)
+		if t, err = p.lookahead(); err != nil {
+			return err
+		}
+	}
+
+	attrs, err := p.attrs()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range chain {
+		g.addNode(id)
+	}
+	for i := 1; i < len(chain); i++ {
+		g.Edges = append(g.Edges, [2]string{chain[i-1], chain[i]})
+	}
+	if label, ok := attrs["label"]; ok && len(chain) == 1 {
+		g.Labels[first.text] = label
+	}
+
+	return nil
+}
+
+// attrs reads optional attribute lists: [a=b, c=d] [e=f].
+func (p *parser) attrs() (map[string]string, error) {
+	result := make(map[string]string)
+
+	for {
+		t, err := p.lookahead()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != '[' {
+			return result, nil
+		}
+		p.peek = nil
+
+		for {
+			if t, err = p.next(); err != nil {
+				return nil, err
+			}
+			if t.kind == ']' {
+				break
+			}
+			if t.kind == ',' || t.kind == ';' {
+				continue
+			}
+			if t.kind != tokenID {
+				return nil, p.errorf(t, "unexpected %q in attributes", t.text)
+			}
+
+			name := t.text
+			if t, err = p.expect('='); err != nil {
+				return nil, err
+			}
+			if t, err = p.expect(tokenID); err != nil {
+				return nil, err
+			}
+			result[name] = t.text
+		}
+	}
+}
+
+func keyword(t token, word string) bool {
+	return t.kind == tokenID && !t.quoted && strings.EqualFold(t.text, word)
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skip(); err != nil {
+		return token{}, err
+	}
+
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.IndexByte("{}[];,=", c) >= 0:
+		l.pos++
+		return token{kind: c, text: string(c), pos: start}, nil
+
+	case c == '-' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '>':
+		l.pos += 2
+		return token{kind: tokenArrow, text: "->", pos: start}, nil
+
+	case c == '-' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '-':
+		return token{}, fmt.Errorf("%w: offset %d: undirected edges are not supported", ErrSyntax, start)
+
+	case c == '"':
+		return l.quoted()
+
+	case isIDChar(rune(c)) || c == '-' || c >= 0x80:
+		l.pos++
+		for l.pos < len(l.src) && (isIDChar(rune(l.src[l.pos])) || l.src[l.pos] >= 0x80) {
+			l.pos++
+		}
+		return token{kind: tokenID, text: l.src[start:l.pos], pos: start}, nil
+	}
+
+	return token{}, fmt.Errorf("%w: offset %d: unexpected character %q", ErrSyntax, start, c)
+}
+
+func (l *lexer) quoted() (token, error) {
+	start := l.pos
+	l.pos++
+
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: tokenID, text: b.String(), quoted: true, pos: start}, nil
+		case c == '\\' && l.pos+1 < len(l.src) && (l.src[l.pos+1] == '"' || l.src[l.pos+1] == '\\'):
+			b.WriteByte(l.src[l.pos+1])
+			l.pos += 2
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+
+	return token{}, fmt.Errorf("%w: offset %d: unterminated string", ErrSyntax, start)
+}
+
+// skip moves past spaces and comments: // line, # line and /* block */.
+func (l *lexer) skip() error {
+	for l.pos < len(l.src) {
+		rest := l.src[l.pos:]
+		switch {
+		case unicode.IsSpace(rune(rest[0])):
+			l.pos++
+		case strings.HasPrefix(rest, "//") || rest[0] == '#':
+			if i := strings.IndexByte(rest, '\n'); i >= 0 {
+				l.pos += i + 1
+			} else {
+				l.pos = len(l.src)
+			}
+		case strings.HasPrefix(rest, "/*"):
+			i := strings.Index(rest[2:], "*/")
+			if i < 0 {
+				return fmt.Errorf("%w: offset %d: unterminated comment", ErrSyntax, l.pos)
+			}
+			l.pos += i + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func isIDChar(c rune) bool {
+	return c == '_' || c == '.' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
@@ -0,0 +1,59 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package dot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_Decode(t *testing.T) {
+	doc, err := Decode(strings.NewReader(`
+		/* build plan */
+		strict digraph build {
+			rankdir = LR
+			node [shape=box];
+			edge [color=gray]
+			# generated
+			fetch [label="Fetch sources"]
+			fetch -> compile -> test [weight=2]
+			compile -> "lint step" // optional
+			"lint step" -> test; deploy
+			test -> deploy
+		}
+	`))
+	casecheck.NoError(t, err)
+
+	casecheck.Equal(t, "build", doc.Name)
+	casecheck.Equal(t, []string{"fetch", "compile", "test", "lint step", "deploy"}, doc.Nodes)
+	casecheck.Equal(t, [][2]string{
+		{"fetch", "compile"}, {"compile", "test"},
+		{"compile", "lint step"}, {"lint step", "test"},
+		{"test", "deploy"},
+	}, doc.Edges)
+	casecheck.Equal(t, map[string]string{"fetch": "Fetch sources"}, doc.Labels)
+}
+
+func TestUnit_Decode_Errors(t *testing.T) {
+	for _, src := range []string{
+		`graph { a -- b }`,
+		`digraph { a -- b }`,
+		`digraph { subgraph x { a } }`,
+		`digraph { a -> }`,
+		`digraph { a [label=] }`,
+		`digraph { "a }`,
+		`digraph { a /* }`,
+		`digraph { a }  b`,
+		`digraph { a:p -> b }`,
+		`digraph { a`,
+	} {
+		_, err := Decode(strings.NewReader(src))
+		casecheck.True(t, errors.Is(err, ErrSyntax), "%s: %v", src, err)
+	}
+}
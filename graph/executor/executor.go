@@ -0,0 +1,170 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// Исполнитель запускает задачи по графу зависимостей: узел стартует только после
+// успешного завершения всех его предшественников, одновременно работает
+// не больше заданного числа задач. При ошибке узла все зависящие от него узлы
+// пропускаются, а независимые ветви продолжают выполняться.
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// ErrDependencyCycle is the cause of a run where some nodes never became
+// ready because their dependencies form a cycle.
+var ErrDependencyCycle = errors.New("dependency cycle: nodes never became ready")
+
+// Graph describes the dependencies: Nodes sets the order in which ready nodes
+// are started and a node runs only after every node listing it in Successors.
+type Graph[K comparable] interface {
+	Nodes() iter.Seq[K]
+	Successors(node K) []K
+}
+
+// RunError reports the nodes that failed and the nodes that were not run
+// because of a failed dependency, a cancelled context or a cycle.
+type RunError[K comparable] struct {
+	Failed  map[K]error
+	Skipped []K
+	Cause   error
+}
+
+func (e *RunError[K]) Error() string {
+	msg := fmt.Sprintf("run failed: %d failed, %d skipped", len(e.Failed), len(e.Skipped))
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+func (e *RunError[K]) Unwrap() []error {
+	result := make([]error, 0, len(e.Failed)+1)
+	for _, err := range e.Failed {
+		result = append(result, err)
+	}
+	if e.Cause != nil {
+		result = append(result, e.Cause)
+	}
+	return result
+}
+
+// Run calls fn for every node of the graph with at most workers calls at once.
+// Ready nodes are started in the order of g.Nodes.
+func Run[K comparable](ctx context.Context, g Graph[K], workers int, fn func(ctx context.Context, node K) error) error {
+	workers = max(workers, 1)
+
+	nodes := slices.Collect(g.Nodes())
+	successors := make(map[K][]K, len(nodes))
+	inDegree := make(map[K]int, len(nodes))
+	for _, node := range nodes {
+		successors[node] = g.Successors(node)
+		for _, next := range successors[node] {
+			inDegree[next]++
+		}
+	}
+
+	ready := make([]K, 0, len(nodes))
+	for _, node := range nodes {
+		if inDegree[node] == 0 {
+			ready = append(ready, node)
+		}
+	}
+
+	type result struct {
+		node K
+		err  error
+	}
+
+	var (
+		results = make(chan result)
+		running int
+		done    = make(map[K]struct{}, len(nodes))
+		skipped = make(map[K]struct{})
+		failed  = make(map[K]error)
+	)
+
+	skip := func(node K) {
+		queue := slices.Clone(successors[node])
+		for len(queue) > 0 {
+			next := queue[0]
+			queue = queue[1:]
+			if _, ok := skipped[next]; ok {
+				continue
+			}
+			skipped[next] = struct{}{}
+			queue = append(queue, successors[next]...)
+		}
+	}
+
+	for len(ready) > 0 || running > 0 {
+		for len(ready) > 0 && running < workers && ctx.Err() == nil {
+			node := ready[0]
+			ready = ready[1:]
+			running++
+
+			go func() {
+				results <- result{node: node, err: call(ctx, node, fn)}
+			}()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		res := <-results
+		running--
+		done[res.node] = struct{}{}
+
+		if res.err != nil {
+			failed[res.node] = res.err
+			skip(res.node)
+			continue
+		}
+
+		for _, next := range successors[res.node] {
+			inDegree[next]--
+			if _, ok := skipped[next]; !ok && inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	runErr := &RunError[K]{Failed: failed}
+	for _, node := range nodes {
+		if _, ok := done[node]; !ok {
+			runErr.Skipped = append(runErr.Skipped, node)
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil && len(runErr.Skipped) > 0:
+		runErr.Cause = ctx.Err()
+	case len(runErr.Skipped) > len(skipped):
+		runErr.Cause = ErrDependencyCycle
+	case len(failed) == 0:
+		return nil
+	}
+
+	return runErr
+}
+
+func call[K comparable](ctx context.Context, node K, fn func(ctx context.Context, node K) error) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("node %v: panic: %v", node, e)
+		}
+	}()
+
+	if err = fn(ctx, node); err != nil {
+		return fmt.Errorf("node %v: %w", node, err)
+	}
+	return nil
+}
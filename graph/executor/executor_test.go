@@ -0,0 +1,152 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+
+	"go.osspkg.com/algorithms/graph/dfs"
+)
+
+func TestUnit_Run(t *testing.T) {
+	g := dfs.NewGraph[string]()
+	for _, node := range []string{"a", "b", "c", "d", "e", "f"} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	for _, edge := range [][2]string{{"a", "c"}, {"b", "c"}, {"c", "d"}, {"c", "e"}, {"d", "f"}, {"e", "f"}} {
+		casecheck.NoError(t, g.AddEdge(edge[0], edge[1]))
+	}
+
+	var (
+		mux      sync.Mutex
+		finished = make(map[string]bool)
+		active   atomic.Int32
+		peak     atomic.Int32
+	)
+
+	err := Run(context.Background(), g, 2, func(_ context.Context, node string) error {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		mux.Lock()
+		for _, prev := range g.Predecessors(node) {
+			if !finished[prev] {
+				t.Errorf("%s started before %s finished", node, prev)
+			}
+		}
+		mux.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mux.Lock()
+		finished[node] = true
+		mux.Unlock()
+		return nil
+	})
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, 6, len(finished))
+	casecheck.Equal(t, int32(2), peak.Load())
+}
+
+func TestUnit_Run_Failure(t *testing.T) {
+	g := dfs.NewGraph[string]()
+	for _, node := range []string{"a", "b", "c", "d", "x", "y"} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	for _, edge := range [][2]string{{"a", "b"}, {"b", "c"}, {"a", "d"}, {"x", "y"}} {
+		casecheck.NoError(t, g.AddEdge(edge[0], edge[1]))
+	}
+
+	errBroken := errors.New("broken")
+
+	var (
+		mux sync.Mutex
+		ran []string
+	)
+	err := Run(context.Background(), g, 1, func(_ context.Context, node string) error {
+		mux.Lock()
+		ran = append(ran, node)
+		mux.Unlock()
+
+		switch node {
+		case "b":
+			return errBroken
+		case "y":
+			panic("boom")
+		}
+		return nil
+	})
+
+	var runErr *RunError[string]
+	casecheck.True(t, errors.As(err, &runErr))
+	casecheck.True(t, errors.Is(err, errBroken))
+	casecheck.Equal(t, 2, len(runErr.Failed))
+	casecheck.Equal(t, "node y: panic: boom", runErr.Failed["y"].Error())
+	casecheck.Equal(t, []string{"c"}, runErr.Skipped)
+	casecheck.Equal(t, []string{"a", "x", "b", "d", "y"}, ran)
+	casecheck.True(t, runErr.Cause == nil)
+}
+
+func TestUnit_Run_Cycle(t *testing.T) {
+	g := dfs.NewGraph[string]()
+	for _, node := range []string{"a", "b", "c", "d"} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	for _, edge := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "b"}, {"c", "d"}} {
+		casecheck.NoError(t, g.AddEdge(edge[0], edge[1]))
+	}
+
+	var count atomic.Int32
+	err := Run(context.Background(), g, 4, func(context.Context, string) error {
+		count.Add(1)
+		return nil
+	})
+
+	var runErr *RunError[string]
+	casecheck.True(t, errors.As(err, &runErr))
+	casecheck.True(t, errors.Is(err, ErrDependencyCycle))
+	casecheck.Equal(t, []string{"b", "c", "d"}, runErr.Skipped)
+	casecheck.Equal(t, int32(1), count.Load())
+}
+
+func TestUnit_Run_Cancel(t *testing.T) {
+	g := dfs.NewGraph[string]()
+	for _, node := range []string{"a", "b", "c"} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	for _, edge := range [][2]string{{"a", "b"}, {"b", "c"}} {
+		casecheck.NoError(t, g.AddEdge(edge[0], edge[1]))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := Run(ctx, g, 1, func(_ context.Context, node string) error {
+		if node == "a" {
+			cancel()
+		}
+		return nil
+	})
+
+	var runErr *RunError[string]
+	casecheck.True(t, errors.As(err, &runErr))
+	casecheck.True(t, errors.Is(err, context.Canceled))
+	casecheck.Equal(t, []string{"b", "c"}, runErr.Skipped)
+	casecheck.Equal(t, 0, len(runErr.Failed))
+}
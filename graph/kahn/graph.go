@@ -8,60 +8,166 @@
 package kahn
 
 import (
+	"cmp"
 	"errors"
-	"sort"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
 )
 
 var (
 	ErrBuild      = errors.New("can't do topographical sorting")
 	ErrBreakPoint = errors.New("don`t found topographical break point")
+	ErrSelfLoop   = errors.New("self-loop is not allowed")
+	ErrEdge       = errors.New("edge not found")
 )
 
-type Graph struct {
-	from map[string][]string
-	to   map[string][]string
+type Graph[K cmp.Ordered] struct {
+	from map[K][]K
+	to   map[K][]K
 
-	nodes map[string]struct{}
+	nodes map[K]struct{}
 
-	breakPoint string
-	result     []string
+	breakPoints  []K
+	changePoints []K
+	result       []K
+	layers       [][]K
 }
 
-func New() *Graph {
-	return &Graph{
-		from:   make(map[string][]string),
-		to:     make(map[string][]string),
-		nodes:  make(map[string]struct{}),
-		result: make([]string, 0),
+func New[K cmp.Ordered]() *Graph[K] {
+	return &Graph[K]{
+		from:   make(map[K][]K),
+		to:     make(map[K][]K),
+		nodes:  make(map[K]struct{}),
+		result: make([]K, 0),
 	}
 }
 
-func (g *Graph) Add(from, to string) {
-	g.from[from] = append(g.from[from], to)
-	g.to[to] = append(g.to[to], from)
+// AddNode adds a node without edges, an existing node is left as is.
+// Add registers the nodes of the edge itself, so AddNode is only needed for isolated nodes.
+func (g *Graph[K]) AddNode(key K) error {
+	g.nodes[key] = struct{}{}
+	return nil
+}
+
+// Add adds the edge, a repeated edge is ignored and a self-loop is rejected with *SelfLoopError.
+func (g *Graph[K]) Add(from, to K) error {
+	if from == to {
+		return &SelfLoopError[K]{Node: from}
+	}
+
 	g.nodes[from] = struct{}{}
 	g.nodes[to] = struct{}{}
+
+	if slices.Contains(g.from[from], to) {
+		return nil
+	}
+
+	g.from[from] = append(g.from[from], to)
+	g.to[to] = append(g.to[to], from)
+
+	return nil
 }
 
-func (g *Graph) BreakPoint(point string) {
-	g.breakPoint = point
+// Remove deletes the edge, the nodes stay in the graph.
+func (g *Graph[K]) Remove(from, to K) error {
+	i := slices.Index(g.from[from], to)
+	if i < 0 {
+		return fmt.Errorf("%w: %v -> %v", ErrEdge, from, to)
+	}
+
+	g.from[from] = slices.Delete(g.from[from], i, i+1)
+
+	i = slices.Index(g.to[to], from)
+	g.to[to] = slices.Delete(g.to[to], i, i+1)
+
+	return nil
 }
 
-func (g *Graph) Build() error {
+func (g *Graph[K]) Clone() *Graph[K] {
+	c := New[K]()
+
+	for k, v := range g.from {
+		c.from[k] = slices.Clone(v)
+	}
+	for k, v := range g.to {
+		c.to[k] = slices.Clone(v)
+	}
+	for k := range g.nodes {
+		c.nodes[k] = struct{}{}
+	}
+
+	c.breakPoints = slices.Clone(g.breakPoints)
+	c.changePoints = slices.Clone(g.changePoints)
+	c.result = append(c.result, g.result...)
+	c.layers = g.Layers()
+
+	return c
+}
+
+// Nodes iterates over all nodes in sorted order.
+func (g *Graph[K]) Nodes() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, node := range getKeys(g.nodes) {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// Edges iterates over all edges grouped by the sorted source node.
+func (g *Graph[K]) Edges() iter.Seq2[K, K] {
+	return func(yield func(K, K) bool) {
+		for _, from := range getKeys(g.nodes) {
+			for _, to := range g.from[from] {
+				if !yield(from, to) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// BreakPoint limits Build to the given nodes and everything they depend on.
+// Several points give the union of their dependencies, no points reset the limit.
+// The zero value is a regular key: unlike the string-only graph, BreakPoint("")
+// is a point that must exist in the graph, use BreakPoint() to reset.
+func (g *Graph[K]) BreakPoint(points ...K) {
+	g.breakPoints = append(g.breakPoints[:0], points...)
+}
+
+// ChangePoint limits Build to the given changed nodes and everything that depends on them,
+// which is the set to rebuild after a change. Combined with BreakPoint only the nodes
+// matching both limits are kept. No points reset the limit.
+func (g *Graph[K]) ChangePoint(points ...K) {
+	g.changePoints = append(g.changePoints[:0], points...)
+}
+
+func (g *Graph[K]) Build() error {
 	g.result = g.result[:0]
+	g.layers = g.layers[:0]
+
+	active := g.copyAllNodes()
 
-	var active map[string]struct{}
+	if len(g.breakPoints) > 0 {
+		ancestors, err := g.closure(g.breakPoints, g.to)
+		if err != nil {
+			return err
+		}
+		intersect(active, ancestors)
+	}
 
-	if len(g.breakPoint) == 0 {
-		active = g.copyAllNodes()
-	} else {
-		active = g.copyNodesByBreakPoint()
-		if len(active) == 0 {
-			return ErrBreakPoint
+	if len(g.changePoints) > 0 {
+		descendants, err := g.closure(g.changePoints, g.from)
+		if err != nil {
+			return err
 		}
+		intersect(active, descendants)
 	}
 
-	inDegree := make(map[string]int)
+	inDegree := make(map[K]int)
 	for u := range active {
 		for _, v := range g.from[u] {
 			if _, ok := active[v]; ok {
@@ -70,7 +176,7 @@ func (g *Graph) Build() error {
 		}
 	}
 
-	queue := make([]string, 0, len(active))
+	queue := make([]K, 0, len(active))
 
 	for _, key := range getKeys(active) {
 		if inDegree[key] == 0 {
@@ -78,6 +184,8 @@ func (g *Graph) Build() error {
 		}
 	}
 
+	level := make(map[K]int, len(active))
+
 	for len(queue) > 0 {
 		key := queue[0]
 		queue = queue[1:]
@@ -86,6 +194,7 @@ func (g *Graph) Build() error {
 
 		for _, v := range g.from[key] {
 			if _, ok := active[v]; ok {
+				level[v] = max(level[v], level[key]+1)
 				inDegree[v]--
 				if inDegree[v] == 0 {
 					queue = append(queue, v)
@@ -95,33 +204,120 @@ func (g *Graph) Build() error {
 	}
 
 	if len(g.result) != len(active) {
-		return ErrBuild
+		return &CycleError[K]{Path: g.findCycle(active, inDegree)}
+	}
+
+	for _, key := range g.result {
+		for len(g.layers) <= level[key] {
+			g.layers = append(g.layers, nil)
+		}
+		g.layers[level[key]] = append(g.layers[level[key]], key)
 	}
 
 	return nil
 }
 
-func (g *Graph) Result() []string {
-	return append(make([]string, 0, len(g.result)), g.result...)
+// CycleError describes a cycle that prevents sorting. Path starts and ends
+// with the same node: a -> b -> c -> a.
+type CycleError[K comparable] struct {
+	Path []K
 }
 
-func (g *Graph) copyAllNodes() map[string]struct{} {
-	tmp := make(map[string]struct{}, len(g.nodes))
+func (e *CycleError[K]) Error() string {
+	parts := make([]string, 0, len(e.Path))
+	for _, node := range e.Path {
+		parts = append(parts, fmt.Sprint(node))
+	}
+	return fmt.Sprintf("%s: cycle %s", ErrBuild.Error(), strings.Join(parts, " -> "))
+}
+
+func (e *CycleError[K]) Unwrap() error {
+	return ErrBuild
+}
+
+type SelfLoopError[K comparable] struct {
+	Node K
+}
+
+func (e *SelfLoopError[K]) Error() string {
+	return fmt.Sprintf("%s: %v -> %v", ErrSelfLoop.Error(), e.Node, e.Node)
+}
+
+func (e *SelfLoopError[K]) Unwrap() error {
+	return ErrSelfLoop
+}
+
+// findCycle walks back over unsorted predecessors: every node left after sorting
+// has one, so the walk must return to a node it has already passed.
+func (g *Graph[K]) findCycle(active map[K]struct{}, inDegree map[K]int) []K {
+	var walk []K
+	seen := make(map[K]int)
+
+	for _, key := range getKeys(active) {
+		if inDegree[key] > 0 {
+			walk = append(walk, key)
+			break
+		}
+	}
+
+	for len(walk) > 0 {
+		key := walk[len(walk)-1]
+		seen[key] = len(walk) - 1
+
+		next := len(walk)
+		for _, prev := range g.to[key] {
+			if _, ok := active[prev]; !ok || inDegree[prev] == 0 {
+				continue
+			}
+			if i, ok := seen[prev]; ok {
+				cycle := append(walk[i:], prev)
+				slices.Reverse(cycle)
+				return cycle
+			}
+			walk = append(walk, prev)
+			break
+		}
+		if next == len(walk) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (g *Graph[K]) Result() []K {
+	return append(make([]K, 0, len(g.result)), g.result...)
+}
+
+// Layers returns the result of Build split into levels: every node of a level
+// depends only on nodes of earlier levels, so a level can be processed in parallel.
+func (g *Graph[K]) Layers() [][]K {
+	result := make([][]K, 0, len(g.layers))
+	for _, layer := range g.layers {
+		result = append(result, append(make([]K, 0, len(layer)), layer...))
+	}
+	return result
+}
+
+func (g *Graph[K]) copyAllNodes() map[K]struct{} {
+	tmp := make(map[K]struct{}, len(g.nodes))
 	for k := range g.nodes {
 		tmp[k] = struct{}{}
 	}
 	return tmp
 }
 
-func (g *Graph) copyNodesByBreakPoint() map[string]struct{} {
-	if _, ok := g.nodes[g.breakPoint]; !ok {
-		return nil
+// closure returns the points with all nodes reachable from them over the given edges.
+func (g *Graph[K]) closure(points []K, edges map[K][]K) (map[K]struct{}, error) {
+	for _, point := range points {
+		if _, ok := g.nodes[point]; !ok {
+			return nil, fmt.Errorf("%w: %v", ErrBreakPoint, point)
+		}
 	}
 
-	queue := make([]string, 0, len(g.nodes))
-	tmp := make(map[string]struct{}, len(g.nodes))
+	queue := append(make([]K, 0, len(g.nodes)), points...)
+	tmp := make(map[K]struct{}, len(g.nodes))
 
-	queue = append(queue, g.breakPoint)
 	for len(queue) > 0 {
 		key := queue[0]
 		queue = queue[1:]
@@ -130,16 +326,24 @@ func (g *Graph) copyNodesByBreakPoint() map[string]struct{} {
 			continue
 		}
 		tmp[key] = struct{}{}
-		queue = append(queue, g.to[key]...)
+		queue = append(queue, edges[key]...)
+	}
+	return tmp, nil
+}
+
+func intersect[K comparable](dst, src map[K]struct{}) {
+	for k := range dst {
+		if _, ok := src[k]; !ok {
+			delete(dst, k)
+		}
 	}
-	return tmp
 }
 
-func getKeys(in map[string]struct{}) []string {
-	result := make([]string, 0, len(in))
+func getKeys[K cmp.Ordered](in map[K]struct{}) []K {
+	result := make([]K, 0, len(in))
 	for k := range in {
 		result = append(result, k)
 	}
-	sort.Strings(result)
+	slices.Sort(result)
 	return result
 }
@@ -7,7 +7,9 @@ package kahn_test
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 
 	"go.osspkg.com/algorithms/graph/kahn"
@@ -17,7 +19,7 @@ func Benchmark_Kahn1(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		func() {
-			graph := kahn.New()
+			graph := kahn.New[string]()
 			graph.Add("1", "2")
 			graph.Add("1", "3")
 			graph.Add("3", "4")
@@ -32,7 +34,7 @@ func Benchmark_Kahn2(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		func() {
-			graph := kahn.New()
+			graph := kahn.New[string]()
 			graph.Add("1", "2")
 			graph.Add("1", "3")
 			graph.Add("3", "4")
@@ -47,14 +49,14 @@ func Benchmark_Kahn2(b *testing.B) {
 func TestUnit_Graph_Build(t *testing.T) {
 	tests := []struct {
 		name       string
-		setup      func(g *kahn.Graph)
+		setup      func(g *kahn.Graph[string])
 		breakPoint string
 		want       []string
 		wantErr    error
 	}{
 		{
 			name: "Happy path: simple linear dependencies",
-			setup: func(g *kahn.Graph) {
+			setup: func(g *kahn.Graph[string]) {
 				g.Add("A", "B") // A -> B
 				g.Add("B", "C") // B -> C
 			},
@@ -62,7 +64,7 @@ func TestUnit_Graph_Build(t *testing.T) {
 		},
 		{
 			name: "Happy path: multiple roots",
-			setup: func(g *kahn.Graph) {
+			setup: func(g *kahn.Graph[string]) {
 				g.Add("A", "C")
 				g.Add("B", "C")
 			},
@@ -71,7 +73,7 @@ func TestUnit_Graph_Build(t *testing.T) {
 		},
 		{
 			name: "Cycle detection",
-			setup: func(g *kahn.Graph) {
+			setup: func(g *kahn.Graph[string]) {
 				g.Add("A", "B")
 				g.Add("B", "C")
 				g.Add("C", "A") // Cycle
@@ -80,7 +82,7 @@ func TestUnit_Graph_Build(t *testing.T) {
 		},
 		{
 			name: "BreakPoint: subset of graph",
-			setup: func(g *kahn.Graph) {
+			setup: func(g *kahn.Graph[string]) {
 				g.Add("Base", "Lib")
 				g.Add("Lib", "App")
 				g.Add("Other", "Unused")
@@ -90,7 +92,7 @@ func TestUnit_Graph_Build(t *testing.T) {
 		},
 		{
 			name: "BreakPoint: complex dependencies",
-			setup: func(g *kahn.Graph) {
+			setup: func(g *kahn.Graph[string]) {
 				g.Add("Common", "Auth")
 				g.Add("Common", "DB")
 				g.Add("Auth", "API")
@@ -102,7 +104,7 @@ func TestUnit_Graph_Build(t *testing.T) {
 		},
 		{
 			name: "BreakPoint: not found",
-			setup: func(g *kahn.Graph) {
+			setup: func(g *kahn.Graph[string]) {
 				g.Add("A", "B")
 			},
 			breakPoint: "Z",
@@ -110,7 +112,7 @@ func TestUnit_Graph_Build(t *testing.T) {
 		},
 		{
 			name: "Cycle outside of BreakPoint scope",
-			setup: func(g *kahn.Graph) {
+			setup: func(g *kahn.Graph[string]) {
 				g.Add("A", "B") // Path to BreakPoint
 				g.Add("C", "D") // Cycle
 				g.Add("D", "C") // Cycle
@@ -122,7 +124,7 @@ func TestUnit_Graph_Build(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			g := kahn.New()
+			g := kahn.New[string]()
 			tt.setup(g)
 			if tt.breakPoint != "" {
 				g.BreakPoint(tt.breakPoint)
@@ -147,8 +149,215 @@ func TestUnit_Graph_Build(t *testing.T) {
 	}
 }
 
+func TestUnit_Graph_Points(t *testing.T) {
+	g := kahn.New[int]()
+	g.Add(1, 3)
+	g.Add(2, 3)
+	g.Add(3, 4)
+	g.Add(3, 5)
+	g.Add(10, 5)
+	g.Add(5, 6)
+	g.Add(7, 8)
+
+	tests := []struct {
+		name         string
+		breakPoints  []int
+		changePoints []int
+		want         []int
+		wantErr      error
+	}{
+		{name: "all nodes", want: []int{1, 2, 7, 10, 3, 8, 4, 5, 6}},
+		{name: "union of break points", breakPoints: []int{4, 8}, want: []int{1, 2, 7, 3, 8, 4}},
+		{name: "change point", changePoints: []int{3}, want: []int{3, 4, 5, 6}},
+		{name: "several change points", changePoints: []int{10, 7}, want: []int{7, 10, 8, 5, 6}},
+		{name: "change and break points", breakPoints: []int{5}, changePoints: []int{2}, want: []int{2, 3, 5}},
+		{name: "unknown break point", breakPoints: []int{4, 42}, wantErr: kahn.ErrBreakPoint},
+		{name: "unknown change point", changePoints: []int{42}, wantErr: kahn.ErrBreakPoint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.BreakPoint(tt.breakPoints...)
+			g.ChangePoint(tt.changePoints...)
+
+			err := g.Build()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Build() unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(g.Result(), tt.want) {
+				t.Errorf("Result() = %v, want %v", g.Result(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUnit_Graph_BreakPointReset(t *testing.T) {
+	g := kahn.New[string]()
+	g.Add("A", "B")
+	g.Add("B", "C")
+
+	g.BreakPoint("B")
+	if err := g.Build(); err != nil || !reflect.DeepEqual(g.Result(), []string{"A", "B"}) {
+		t.Fatalf("Build() = %v, %v", g.Result(), err)
+	}
+
+	g.BreakPoint()
+	if err := g.Build(); err != nil || !reflect.DeepEqual(g.Result(), []string{"A", "B", "C"}) {
+		t.Fatalf("Build() after reset = %v, %v", g.Result(), err)
+	}
+
+	// the empty string is a node key, not "no break point"
+	g.BreakPoint("")
+	if err := g.Build(); !errors.Is(err, kahn.ErrBreakPoint) {
+		t.Errorf("Build() error = %v, wantErr %v", err, kahn.ErrBreakPoint)
+	}
+}
+
+func TestUnit_Graph_CycleError(t *testing.T) {
+	g := kahn.New[string]()
+	g.Add("Root", "A")
+	g.Add("A", "B")
+	g.Add("B", "C")
+	g.Add("C", "A")
+	g.Add("C", "Leaf")
+
+	err := g.Build()
+	if !errors.Is(err, kahn.ErrBuild) {
+		t.Fatalf("Build() error = %v, want %v", err, kahn.ErrBuild)
+	}
+
+	var cycleErr *kahn.CycleError[string]
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Build() error = %T, want *CycleError", err)
+	}
+
+	want := []string{"A", "B", "C", "A"}
+	if !reflect.DeepEqual(cycleErr.Path, want) {
+		t.Errorf("Path = %v, want %v", cycleErr.Path, want)
+	}
+}
+
+func TestUnit_Graph_Layers(t *testing.T) {
+	g := kahn.New[string]()
+	g.Add("Common", "Auth")
+	g.Add("Common", "DB")
+	g.Add("Auth", "API")
+	g.Add("DB", "API")
+	g.Add("Common", "API")
+	g.Add("Utils", "Web")
+	g.Add("API", "Web")
+
+	if err := g.Build(); err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+
+	want := [][]string{{"Common", "Utils"}, {"Auth", "DB"}, {"API"}, {"Web"}}
+	if !reflect.DeepEqual(g.Layers(), want) {
+		t.Errorf("Layers() = %v, want %v", g.Layers(), want)
+	}
+
+	g.BreakPoint("DB")
+	if err := g.Build(); err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+
+	want = [][]string{{"Common"}, {"DB"}}
+	if !reflect.DeepEqual(g.Layers(), want) {
+		t.Errorf("Layers() = %v, want %v", g.Layers(), want)
+	}
+}
+
+func TestUnit_Graph_Edges(t *testing.T) {
+	g := kahn.New[string]()
+	if err := g.Add("A", "B"); err != nil {
+		t.Fatalf("Add() unexpected error: %v", err)
+	}
+	_ = g.Add("A", "B")
+
+	err := g.Add("C", "C")
+	var loopErr *kahn.SelfLoopError[string]
+	if !errors.As(err, &loopErr) || !errors.Is(err, kahn.ErrSelfLoop) || loopErr.Node != "C" {
+		t.Fatalf("Add() error = %v, want self-loop error", err)
+	}
+
+	if err = g.Remove("A", "B"); err != nil {
+		t.Fatalf("Remove() unexpected error: %v", err)
+	}
+	if err = g.Remove("A", "B"); !errors.Is(err, kahn.ErrEdge) {
+		t.Fatalf("Remove() error = %v, want %v", err, kahn.ErrEdge)
+	}
+
+	// the repeated edge was stored once, so nothing is left to form a cycle
+	_ = g.Add("B", "A")
+	if err = g.Build(); err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+	if want := []string{"B", "A"}; !reflect.DeepEqual(g.Result(), want) {
+		t.Errorf("Result() = %v, want %v", g.Result(), want)
+	}
+}
+
+func TestUnit_Graph_Clone(t *testing.T) {
+	g := kahn.New[string]()
+	_ = g.Add("A", "B")
+	g.BreakPoint("B")
+	_ = g.Build()
+
+	c := g.Clone()
+	if !reflect.DeepEqual(c.Result(), g.Result()) {
+		t.Errorf("Clone().Result() = %v, want %v", c.Result(), g.Result())
+	}
+
+	_ = c.Add("B", "C")
+	_ = c.Add("C", "A")
+	if err := g.Build(); err != nil {
+		t.Fatalf("Build() of the original graph unexpected error: %v", err)
+	}
+
+	c.BreakPoint()
+	if err := c.Build(); !errors.Is(err, kahn.ErrBuild) {
+		t.Fatalf("Build() of the clone error = %v, want %v", err, kahn.ErrBuild)
+	}
+}
+
+func TestUnit_SyncGraph(t *testing.T) {
+	g := kahn.NewSync[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = g.Add(i*100+j, i*100+j+1)
+				_ = g.Build()
+				_ = g.Result()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := g.Build(); err != nil {
+		t.Fatalf("Build() unexpected error: %v", err)
+	}
+	if got := len(g.Result()); got != 1001 {
+		t.Errorf("len(Result()) = %d, want %d", got, 1001)
+	}
+	if got := fmt.Sprint(g.Layers()[0]); got != "[0]" {
+		t.Errorf("Layers()[0] = %s, want [0]", got)
+	}
+}
+
 func TestUnit_Graph_ResultIsCopy(t *testing.T) {
-	g := kahn.New()
+	g := kahn.New[string]()
 	g.Add("A", "B")
 	_ = g.Build()
 
@@ -0,0 +1,47 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package kahn
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonNode[K any] struct {
+	Node  K   `json:"node"`
+	Edges []K `json:"edges,omitempty"`
+}
+
+// MarshalJSON encodes the graph as an adjacency list with sorted nodes,
+// so the same graph always gives the same output.
+func (g *Graph[K]) MarshalJSON() ([]byte, error) {
+	list := make([]jsonNode[K], 0, len(g.nodes))
+	for _, node := range getKeys(g.nodes) {
+		list = append(list, jsonNode[K]{Node: node, Edges: g.from[node]})
+	}
+	return json.Marshal(list)
+}
+
+// UnmarshalJSON replaces the graph with the decoded adjacency list.
+func (g *Graph[K]) UnmarshalJSON(data []byte) error {
+	var list []jsonNode[K]
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("decode graph: %w", err)
+	}
+
+	tmp := New[K]()
+	for _, item := range list {
+		tmp.nodes[item.Node] = struct{}{}
+		for _, to := range item.Edges {
+			if err := tmp.Add(item.Node, to); err != nil {
+				return err
+			}
+		}
+	}
+
+	*g = *tmp
+	return nil
+}
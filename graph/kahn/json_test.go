@@ -0,0 +1,63 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package kahn_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.osspkg.com/algorithms/graph/kahn"
+)
+
+func TestUnit_Graph_JSON(t *testing.T) {
+	g := kahn.New[int]()
+	_ = g.Add(3, 1)
+	_ = g.Add(3, 2)
+	_ = g.Add(2, 1)
+	_ = g.Add(4, 5)
+	_ = g.Remove(4, 5)
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+
+	want := `[{"node":1},{"node":2,"edges":[1]},{"node":3,"edges":[1,2]},{"node":4},{"node":5}]`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	restored := kahn.New[int]()
+	if err = json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+
+	again, _ := json.Marshal(restored)
+	if string(again) != want {
+		t.Errorf("Marshal() after round trip = %s, want %s", again, want)
+	}
+
+	_ = g.Build()
+	_ = restored.Build()
+	if !reflect.DeepEqual(g.Result(), restored.Result()) {
+		t.Errorf("Result() = %v, want %v", restored.Result(), g.Result())
+	}
+
+	err = json.Unmarshal([]byte(`[{"node":1,"edges":[1]}]`), restored)
+	if !errors.Is(err, kahn.ErrSelfLoop) {
+		t.Errorf("Unmarshal() error = %v, want %v", err, kahn.ErrSelfLoop)
+	}
+
+	sg := kahn.NewSync[int]()
+	if err = json.Unmarshal(data, sg); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if again, _ = json.Marshal(sg); string(again) != want {
+		t.Errorf("SyncGraph Marshal() = %s, want %s", again, want)
+	}
+}
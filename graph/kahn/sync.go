@@ -0,0 +1,122 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package kahn
+
+import (
+	"cmp"
+	"iter"
+	"sync"
+)
+
+// SyncGraph is a Graph safe for concurrent use.
+type SyncGraph[K cmp.Ordered] struct {
+	g   *Graph[K]
+	mux sync.RWMutex
+}
+
+func NewSync[K cmp.Ordered]() *SyncGraph[K] {
+	return &SyncGraph[K]{g: New[K]()}
+}
+
+func (s *SyncGraph[K]) AddNode(key K) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.g.AddNode(key)
+}
+
+func (s *SyncGraph[K]) Add(from, to K) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.g.Add(from, to)
+}
+
+func (s *SyncGraph[K]) Remove(from, to K) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.g.Remove(from, to)
+}
+
+func (s *SyncGraph[K]) BreakPoint(points ...K) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.g.BreakPoint(points...)
+}
+
+func (s *SyncGraph[K]) ChangePoint(points ...K) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.g.ChangePoint(points...)
+}
+
+func (s *SyncGraph[K]) Build() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.g.Build()
+}
+
+func (s *SyncGraph[K]) Result() []K {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return s.g.Result()
+}
+
+func (s *SyncGraph[K]) Layers() [][]K {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return s.g.Layers()
+}
+
+// Clone returns an independent copy of the underlying graph.
+func (s *SyncGraph[K]) Clone() *Graph[K] {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return s.g.Clone()
+}
+
+// Nodes iterates over a snapshot of the nodes.
+func (s *SyncGraph[K]) Nodes() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for node := range s.Clone().Nodes() {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+// Edges iterates over a snapshot of the edges.
+func (s *SyncGraph[K]) Edges() iter.Seq2[K, K] {
+	return func(yield func(K, K) bool) {
+		for from, to := range s.Clone().Edges() {
+			if !yield(from, to) {
+				return
+			}
+		}
+	}
+}
+
+func (s *SyncGraph[K]) MarshalJSON() ([]byte, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return s.g.MarshalJSON()
+}
+
+func (s *SyncGraph[K]) UnmarshalJSON(data []byte) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.g.UnmarshalJSON(data)
+}
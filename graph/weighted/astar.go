@@ -0,0 +1,36 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://en.wikipedia.org/wiki/A*_search_algorithm
+
+package weighted
+
+import "fmt"
+
+// AStar finds the shortest path from the source to the target. The heuristic estimates
+// the remaining cost to the target and must be consistent: it never overestimates
+// and never drops by more than the weight of an edge, otherwise the found path
+// may be not the shortest. Edge weights must be non-negative.
+func (g *Graph[K, W]) AStar(source, target K, heuristic func(node K) W) ([]K, W, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.nodes[source]; !ok {
+		return nil, 0, fmt.Errorf("%w: %v", ErrNodeNotFound, source)
+	}
+	if _, ok := g.nodes[target]; !ok {
+		return nil, 0, fmt.Errorf("%w: %v", ErrNodeNotFound, target)
+	}
+
+	paths, found, err := g.search(source, &target, heuristic)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !found {
+		return nil, 0, fmt.Errorf("%w: %v -> %v", ErrNoPath, source, target)
+	}
+
+	return paths.PathTo(target), paths.dist[target], nil
+}
@@ -0,0 +1,102 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://en.wikipedia.org/wiki/Bellman%E2%80%93Ford_algorithm
+
+package weighted
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NegativeCycleError describes a negative cycle reachable from the source.
+// Path starts and ends with the same node: a -> b -> c -> a.
+type NegativeCycleError[K comparable] struct {
+	Path []K
+}
+
+func (e *NegativeCycleError[K]) Error() string {
+	parts := make([]string, 0, len(e.Path))
+	for _, node := range e.Path {
+		parts = append(parts, fmt.Sprint(node))
+	}
+	return fmt.Sprintf("%s: %s", ErrNegativeCycle.Error(), strings.Join(parts, " -> "))
+}
+
+func (e *NegativeCycleError[K]) Unwrap() error {
+	return ErrNegativeCycle
+}
+
+// BellmanFord finds the shortest paths from the source and allows negative weights.
+// A negative cycle reachable from the source is reported as *NegativeCycleError.
+func (g *Graph[K, W]) BellmanFord(source K) (*Paths[K, W], error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.nodes[source]; !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNodeNotFound, source)
+	}
+
+	paths := &Paths[K, W]{
+		source: source,
+		dist:   map[K]W{source: 0},
+		prev:   make(map[K]K),
+	}
+
+	nodes := g.sortedNodes()
+	edges := make([][]K, len(nodes))
+	for i, node := range nodes {
+		edges[i] = g.edges(node)
+	}
+
+	relax := func() (K, bool) {
+		var (
+			last    K
+			changed bool
+		)
+		for i, from := range nodes {
+			base, ok := paths.dist[from]
+			if !ok {
+				continue
+			}
+			for _, to := range edges[i] {
+				dist := base + g.adjacency[from][to].weight
+				if current, ok := paths.dist[to]; ok && current <= dist {
+					continue
+				}
+				paths.dist[to] = dist
+				paths.prev[to] = from
+				last, changed = to, true
+			}
+		}
+		return last, changed
+	}
+
+	for i := 1; i < len(nodes); i++ {
+		if _, changed := relax(); !changed {
+			return paths, nil
+		}
+	}
+
+	last, changed := relax()
+	if !changed {
+		return paths, nil
+	}
+
+	// after n steps back along prev the node is guaranteed to be on the cycle
+	for i := 0; i < len(nodes); i++ {
+		last = paths.prev[last]
+	}
+	cycle := []K{last}
+	for node := paths.prev[last]; node != last; node = paths.prev[node] {
+		cycle = append(cycle, node)
+	}
+	cycle = append(cycle, last)
+	slices.Reverse(cycle)
+
+	return nil, &NegativeCycleError[K]{Path: cycle}
+}
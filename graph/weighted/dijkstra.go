@@ -0,0 +1,115 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://en.wikipedia.org/wiki/Dijkstra%27s_algorithm
+
+package weighted
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// Dijkstra finds the shortest paths from the source to every reachable node.
+// All edge weights must be non-negative.
+func (g *Graph[K, W]) Dijkstra(source K) (*Paths[K, W], error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.nodes[source]; !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNodeNotFound, source)
+	}
+
+	paths, _, err := g.search(source, nil, func(K) W { return 0 })
+	return paths, err
+}
+
+// search runs Dijkstra guided by the heuristic and stops early when the target is settled,
+// the caller must hold the lock.
+func (g *Graph[K, W]) search(source K, target *K, heuristic func(K) W) (*Paths[K, W], bool, error) {
+	paths := &Paths[K, W]{
+		source: source,
+		dist:   map[K]W{source: 0},
+		prev:   make(map[K]K),
+	}
+	done := make(map[K]struct{}, len(g.nodes))
+
+	queue := &priorityQueue[K, W]{}
+	heap.Push(queue, queueItem[K, W]{node: source, priority: heuristic(source), seq: g.nodes[source]})
+
+	for queue.Len() > 0 {
+		node := queue.list[0].node
+		heap.Pop(queue)
+
+		if _, ok := done[node]; ok {
+			continue
+		}
+		done[node] = struct{}{}
+
+		if target != nil && node == *target {
+			return paths, true, nil
+		}
+
+		for _, neighbor := range g.edges(node) {
+			weight := g.adjacency[node][neighbor].weight
+			if weight < 0 {
+				return nil, false, fmt.Errorf("%w: %v -> %v", ErrNegativeWeight, node, neighbor)
+			}
+			if _, ok := done[neighbor]; ok {
+				continue
+			}
+
+			dist := paths.dist[node] + weight
+			if current, ok := paths.dist[neighbor]; ok && current <= dist {
+				continue
+			}
+
+			paths.dist[neighbor] = dist
+			paths.prev[neighbor] = node
+			heap.Push(queue, queueItem[K, W]{
+				node:     neighbor,
+				priority: dist + heuristic(neighbor),
+				seq:      g.nodes[neighbor],
+			})
+		}
+	}
+
+	return paths, false, nil
+}
+
+type queueItem[K comparable, W Weight] struct {
+	node     K
+	priority W
+	seq      uint64
+}
+
+type priorityQueue[K comparable, W Weight] struct {
+	list []queueItem[K, W]
+}
+
+func (q *priorityQueue[K, W]) Len() int { return len(q.list) }
+
+func (q *priorityQueue[K, W]) Less(i, j int) bool {
+	if q.list[i].priority != q.list[j].priority {
+		return q.list[i].priority < q.list[j].priority
+	}
+	return q.list[i].seq < q.list[j].seq
+}
+
+func (q *priorityQueue[K, W]) Swap(i, j int) { q.list[i], q.list[j] = q.list[j], q.list[i] }
+
+func (q *priorityQueue[K, W]) Push(x any) {
+	v, ok := x.(queueItem[K, W])
+	if !ok {
+		panic("invalid queue item")
+	}
+	q.list = append(q.list, v)
+}
+
+func (q *priorityQueue[K, W]) Pop() any {
+	x := q.list[len(q.list)-1]
+	q.list = q.list[:len(q.list)-1]
+	return x
+}
@@ -0,0 +1,155 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package weighted
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	ErrNodeNotFound   = errors.New("node not found")
+	ErrNodeKeyExist   = errors.New("node key exist")
+	ErrNegativeWeight = errors.New("negative edge weight")
+	ErrNegativeCycle  = errors.New("negative cycle detected")
+	ErrNoPath         = errors.New("no path found")
+)
+
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+type edge[W Weight] struct {
+	weight W
+	seq    uint64
+}
+
+// Graph is a directed graph with weighted edges. Like dfs.Graph it keeps
+// the insertion sequence, so equal-cost paths are resolved the same way every run.
+type Graph[K comparable, W Weight] struct {
+	mu        sync.RWMutex
+	seq       uint64
+	nodes     map[K]uint64
+	adjacency map[K]map[K]edge[W]
+}
+
+func NewGraph[K comparable, W Weight]() *Graph[K, W] {
+	return &Graph[K, W]{
+		nodes:     make(map[K]uint64, 10),
+		adjacency: make(map[K]map[K]edge[W], 10),
+	}
+}
+
+func (g *Graph[K, W]) AddNode(key K) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.nodes[key]; ok {
+		return fmt.Errorf("%w: %v", ErrNodeKeyExist, key)
+	}
+
+	g.seq++
+	g.nodes[key] = g.seq
+	g.adjacency[key] = make(map[K]edge[W])
+
+	return nil
+}
+
+// AddEdge adds the edge or replaces the weight of an existing one.
+func (g *Graph[K, W]) AddEdge(from, to K, weight W) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.nodes[from]; !exists {
+		return fmt.Errorf("%w: %v", ErrNodeNotFound, from)
+	}
+	if _, exists := g.nodes[to]; !exists {
+		return fmt.Errorf("%w: %v", ErrNodeNotFound, to)
+	}
+
+	e, exists := g.adjacency[from][to]
+	if !exists {
+		g.seq++
+		e.seq = g.seq
+	}
+	e.weight = weight
+	g.adjacency[from][to] = e
+
+	return nil
+}
+
+func (g *Graph[K, W]) Weight(from, to K) (W, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	e, ok := g.adjacency[from][to]
+	return e.weight, ok
+}
+
+// Paths holds the result of a single-source search.
+type Paths[K comparable, W Weight] struct {
+	source K
+	dist   map[K]W
+	prev   map[K]K
+}
+
+func (p *Paths[K, W]) Source() K {
+	return p.source
+}
+
+// Distance returns the cost of the shortest path to the node, false if it is unreachable.
+func (p *Paths[K, W]) Distance(to K) (W, bool) {
+	d, ok := p.dist[to]
+	return d, ok
+}
+
+// PathTo returns the nodes of the shortest path from the source to the node,
+// nil if it is unreachable.
+func (p *Paths[K, W]) PathTo(to K) []K {
+	if _, ok := p.dist[to]; !ok {
+		return nil
+	}
+
+	path := []K{to}
+	for to != p.source {
+		to = p.prev[to]
+		path = append(path, to)
+	}
+	slices.Reverse(path)
+
+	return path
+}
+
+// edges returns outgoing edges in insertion order, the caller must hold the lock.
+func (g *Graph[K, W]) edges(node K) []K {
+	neighbors := g.adjacency[node]
+
+	result := make([]K, 0, len(neighbors))
+	for k := range neighbors {
+		result = append(result, k)
+	}
+	slices.SortFunc(result, func(a, b K) int {
+		return cmp.Compare(neighbors[a].seq, neighbors[b].seq)
+	})
+	return result
+}
+
+// sortedNodes returns nodes in insertion order, the caller must hold the lock.
+func (g *Graph[K, W]) sortedNodes() []K {
+	result := make([]K, 0, len(g.nodes))
+	for k := range g.nodes {
+		result = append(result, k)
+	}
+	slices.SortFunc(result, func(a, b K) int {
+		return cmp.Compare(g.nodes[a], g.nodes[b])
+	})
+	return result
+}
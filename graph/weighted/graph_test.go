@@ -0,0 +1,179 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package weighted
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+type testEdge struct {
+	from, to string
+	weight   int
+}
+
+func TestUnit_Graph(t *testing.T) {
+	g := NewGraph[string, int]()
+	casecheck.NoError(t, g.AddNode("a"))
+	casecheck.NoError(t, g.AddNode("b"))
+
+	casecheck.True(t, errors.Is(g.AddNode("a"), ErrNodeKeyExist))
+	casecheck.True(t, errors.Is(g.AddEdge("a", "z", 1), ErrNodeNotFound))
+
+	casecheck.NoError(t, g.AddEdge("a", "b", 5))
+	casecheck.NoError(t, g.AddEdge("a", "b", 3))
+	w, ok := g.Weight("a", "b")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 3, w)
+
+	_, ok = g.Weight("b", "a")
+	casecheck.False(t, ok)
+}
+
+func TestUnit_Dijkstra(t *testing.T) {
+	g := NewGraph[string, int]()
+	for _, node := range []string{"a", "b", "c", "d", "e", "x"} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	for _, e := range []testEdge{
+		{"a", "b", 7}, {"a", "c", 9}, {"a", "e", 14},
+		{"b", "c", 10}, {"b", "d", 15},
+		{"c", "d", 11}, {"c", "e", 2},
+		{"e", "d", 9},
+	} {
+		casecheck.NoError(t, g.AddEdge(e.from, e.to, e.weight))
+	}
+
+	paths, err := g.Dijkstra("a")
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, "a", paths.Source())
+
+	d, ok := paths.Distance("d")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 20, d)
+	casecheck.Equal(t, []string{"a", "c", "d"}, paths.PathTo("d"))
+
+	d, ok = paths.Distance("e")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 11, d)
+	casecheck.Equal(t, []string{"a", "c", "e"}, paths.PathTo("e"))
+	casecheck.Equal(t, []string{"a"}, paths.PathTo("a"))
+
+	_, ok = paths.Distance("x")
+	casecheck.False(t, ok)
+	casecheck.Equal(t, 0, len(paths.PathTo("x")))
+
+	_, err = g.Dijkstra("z")
+	casecheck.True(t, errors.Is(err, ErrNodeNotFound))
+
+	casecheck.NoError(t, g.AddEdge("d", "x", -1))
+	_, err = g.Dijkstra("a")
+	casecheck.True(t, errors.Is(err, ErrNegativeWeight))
+}
+
+func TestUnit_Dijkstra_TieBreak(t *testing.T) {
+	g := NewGraph[string, int]()
+	for _, node := range []string{"s", "b", "a", "t"} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	for _, e := range []testEdge{{"s", "b", 1}, {"s", "a", 1}, {"a", "t", 1}, {"b", "t", 1}} {
+		casecheck.NoError(t, g.AddEdge(e.from, e.to, e.weight))
+	}
+
+	for i := 0; i < 20; i++ {
+		paths, err := g.Dijkstra("s")
+		casecheck.NoError(t, err)
+		casecheck.Equal(t, []string{"s", "b", "t"}, paths.PathTo("t"))
+	}
+}
+
+func TestUnit_BellmanFord(t *testing.T) {
+	g := NewGraph[string, int]()
+	for _, node := range []string{"s", "a", "b", "c", "d"} {
+		casecheck.NoError(t, g.AddNode(node))
+	}
+	for _, e := range []testEdge{
+		{"s", "a", 4}, {"s", "b", 5},
+		{"a", "c", -3}, {"b", "a", -2},
+		{"c", "d", 2}, {"b", "d", 4},
+	} {
+		casecheck.NoError(t, g.AddEdge(e.from, e.to, e.weight))
+	}
+
+	paths, err := g.BellmanFord("s")
+	casecheck.NoError(t, err)
+
+	d, ok := paths.Distance("d")
+	casecheck.True(t, ok)
+	casecheck.Equal(t, 2, d)
+	casecheck.Equal(t, []string{"s", "b", "a", "c", "d"}, paths.PathTo("d"))
+
+	casecheck.NoError(t, g.AddEdge("d", "b", -4))
+	_, err = g.BellmanFord("s")
+	casecheck.True(t, errors.Is(err, ErrNegativeCycle))
+	var cycleErr *NegativeCycleError[string]
+	casecheck.True(t, errors.As(err, &cycleErr))
+	casecheck.Equal(t, []string{"d", "b", "a", "c", "d"}, cycleErr.Path)
+	casecheck.Equal(t, "negative cycle detected: d -> b -> a -> c -> d", err.Error())
+
+	// the cycle is reachable from c too
+	_, err = g.BellmanFord("c")
+	casecheck.True(t, errors.Is(err, ErrNegativeCycle))
+
+	h := NewGraph[string, int]()
+	casecheck.NoError(t, h.AddNode("x"))
+	casecheck.NoError(t, h.AddNode("y"))
+	casecheck.NoError(t, h.AddEdge("x", "y", -1))
+	_, err = h.BellmanFord("y")
+	casecheck.NoError(t, err)
+}
+
+func TestUnit_AStar(t *testing.T) {
+	type point struct{ x, y int }
+
+	const size = 20
+	wall := func(p point) bool { return p.x == 10 && p.y < 15 }
+
+	g := NewGraph[point, float64]()
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if !wall(point{x, y}) {
+				_ = g.AddNode(point{x, y}) //nolint:errcheck
+			}
+		}
+	}
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			for _, d := range []point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+				_ = g.AddEdge(point{x, y}, point{x + d.x, y + d.y}, 1) //nolint:errcheck
+			}
+		}
+	}
+
+	target := point{19, 0}
+	manhattan := func(p point) float64 {
+		return math.Abs(float64(target.x-p.x)) + math.Abs(float64(target.y-p.y))
+	}
+
+	path, cost, err := g.AStar(point{0, 0}, target, manhattan)
+	casecheck.NoError(t, err)
+
+	paths, err := g.Dijkstra(point{0, 0})
+	casecheck.NoError(t, err)
+	want, _ := paths.Distance(target)
+
+	casecheck.Equal(t, want, cost)
+	casecheck.Equal(t, int(cost)+1, len(path))
+	casecheck.Equal(t, point{0, 0}, path[0])
+	casecheck.Equal(t, target, path[len(path)-1])
+
+	casecheck.NoError(t, g.AddNode(point{-5, -5}))
+	_, _, err = g.AStar(point{0, 0}, point{-5, -5}, func(point) float64 { return 0 })
+	casecheck.True(t, errors.Is(err, ErrNoPath))
+}
@@ -0,0 +1,95 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bitmap
+
+func And(a, b *Bitmap) *Bitmap {
+	dst := a.Clone()
+	dst.And(b)
+	return dst
+}
+
+func Or(a, b *Bitmap) *Bitmap {
+	dst := a.Clone()
+	dst.Or(b)
+	return dst
+}
+
+func Xor(a, b *Bitmap) *Bitmap {
+	dst := a.Clone()
+	dst.Xor(b)
+	return dst
+}
+
+func AndNot(a, b *Bitmap) *Bitmap {
+	dst := a.Clone()
+	dst.AndNot(b)
+	return dst
+}
+
+func (b *Bitmap) And(other *Bitmap) {
+	words := other.snapshot()
+
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	for i := range b.bits {
+		if i < len(words) {
+			b.bits[i] &= words[i]
+		} else {
+			b.bits[i] = 0
+		}
+	}
+}
+
+func (b *Bitmap) Or(other *Bitmap) {
+	words := other.snapshot()
+
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	b.grow(uint64(len(words)))
+	for i := 0; i < len(b.bits) && i < len(words); i++ {
+		b.bits[i] |= words[i]
+	}
+}
+
+func (b *Bitmap) Xor(other *Bitmap) {
+	words := other.snapshot()
+
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	b.grow(uint64(len(words)))
+	for i := 0; i < len(b.bits) && i < len(words); i++ {
+		b.bits[i] ^= words[i]
+	}
+}
+
+func (b *Bitmap) AndNot(other *Bitmap) {
+	words := other.snapshot()
+
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	for i := 0; i < len(b.bits) && i < len(words); i++ {
+		b.bits[i] &^= words[i]
+	}
+}
+
+func (b *Bitmap) grow(blocks uint64) {
+	blocks = min(blocks, b.getBlock(b.limit)+1)
+	if blocks > b.blocks {
+		b.resize(blocks*blockSize - 1)
+	}
+}
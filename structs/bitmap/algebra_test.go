@@ -0,0 +1,82 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bitmap
+
+import (
+	"sync"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func newBitmap(indexes ...uint64) *Bitmap {
+	bm := New()
+	for _, i := range indexes {
+		bm.Set(i)
+	}
+	return bm
+}
+
+func listBitmap(bm *Bitmap) []uint64 {
+	result := make([]uint64, 0, bm.Count())
+	for i, ok := bm.NextSet(0); ok; i, ok = bm.NextSet(i + 1) {
+		result = append(result, i)
+	}
+	return result
+}
+
+func TestUnit_Bitmap_Algebra(t *testing.T) {
+	short := []uint64{1, 2, 3, 64}
+	long := []uint64{2, 64, 65, 300}
+
+	tests := []struct {
+		name  string
+		op    func(a, b *Bitmap) *Bitmap
+		inner func(a, b *Bitmap)
+		ab    []uint64
+		ba    []uint64
+	}{
+		{name: "And", op: And, inner: (*Bitmap).And, ab: []uint64{2, 64}, ba: []uint64{2, 64}},
+		{name: "Or", op: Or, inner: (*Bitmap).Or, ab: []uint64{1, 2, 3, 64, 65, 300}, ba: []uint64{1, 2, 3, 64, 65, 300}},
+		{name: "Xor", op: Xor, inner: (*Bitmap).Xor, ab: []uint64{1, 3, 65, 300}, ba: []uint64{1, 3, 65, 300}},
+		{name: "AndNot", op: AndNot, inner: (*Bitmap).AndNot, ab: []uint64{1, 3}, ba: []uint64{65, 300}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := newBitmap(short...), newBitmap(long...)
+
+			casecheck.Equal(t, tt.ab, listBitmap(tt.op(a, b)))
+			casecheck.Equal(t, tt.ba, listBitmap(tt.op(b, a)))
+			casecheck.Equal(t, short, listBitmap(a))
+			casecheck.Equal(t, long, listBitmap(b))
+
+			tt.inner(a, b)
+			casecheck.Equal(t, tt.ab, listBitmap(a))
+
+			a = newBitmap(short...)
+			tt.inner(b, a)
+			casecheck.Equal(t, tt.ba, listBitmap(b))
+		})
+	}
+}
+
+func TestUnit_Bitmap_CrossLock(t *testing.T) {
+	a, b := newBitmap(1, 2, 3), newBitmap(3, 4, 5)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(4)
+		go func() { defer wg.Done(); a.CopyTo(b) }()
+		go func() { defer wg.Done(); b.CopyTo(a) }()
+		go func() { defer wg.Done(); a.Or(b) }()
+		go func() { defer wg.Done(); b.And(a) }()
+	}
+	wg.Wait()
+
+	a.Or(a)
+	a.Xor(a)
+	casecheck.True(t, a.None())
+}
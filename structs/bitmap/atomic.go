@@ -0,0 +1,88 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bitmap
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync/atomic"
+)
+
+// Atomic is a lock-free bitmap with a fixed capacity.
+// Indexes outside the capacity are ignored, and TestAndSet reports them
+// as already set, so they can never be claimed.
+type Atomic struct {
+	words []atomic.Uint64
+	size  uint64
+}
+
+func NewAtomic(size uint64) *Atomic {
+	size = min(size, MaxIndex+1)
+
+	return &Atomic{
+		words: make([]atomic.Uint64, (size+blockSize-1)/blockSize),
+		size:  size,
+	}
+}
+
+func (a *Atomic) Cap() uint64 {
+	return a.size
+}
+
+func (a *Atomic) Set(index uint64) {
+	if index < a.size {
+		a.words[index/blockSize].Or(1 << (index % blockSize))
+	}
+}
+
+func (a *Atomic) Del(index uint64) {
+	if index < a.size {
+		a.words[index/blockSize].And(^uint64(1 << (index % blockSize)))
+	}
+}
+
+func (a *Atomic) Has(index uint64) bool {
+	if index >= a.size {
+		return false
+	}
+	return a.words[index/blockSize].Load()&(1<<(index%blockSize)) != 0
+}
+
+// TestAndSet sets the index and reports whether it was already set.
+func (a *Atomic) TestAndSet(index uint64) bool {
+	if index >= a.size {
+		return true
+	}
+	mask := uint64(1) << (index % blockSize)
+	return a.words[index/blockSize].Or(mask)&mask != 0
+}
+
+// TestAndClear clears the index and reports whether it was set.
+func (a *Atomic) TestAndClear(index uint64) bool {
+	if index >= a.size {
+		return false
+	}
+	mask := uint64(1) << (index % blockSize)
+	return a.words[index/blockSize].And(^mask)&mask != 0
+}
+
+func (a *Atomic) Count() uint64 {
+	var count uint64
+	for i := range a.words {
+		count += uint64(bits.OnesCount64(a.words[i].Load()))
+	}
+	return count
+}
+
+// MarshalBinary uses the same layout as Bitmap.MarshalBinary.
+// The words are loaded one by one, so the result is not a point-in-time snapshot.
+func (a *Atomic) MarshalBinary() ([]byte, error) {
+	out := make([]byte, len(a.words)*8)
+	for i := range a.words {
+		binary.LittleEndian.PutUint64(out[i*8:], a.words[i].Load())
+	}
+	return out, nil
+}
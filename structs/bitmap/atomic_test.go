@@ -0,0 +1,89 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bitmap
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_Atomic(t *testing.T) {
+	bm := NewAtomic(100)
+	casecheck.Equal(t, uint64(100), bm.Cap())
+
+	casecheck.False(t, bm.TestAndSet(5))
+	casecheck.True(t, bm.TestAndSet(5))
+	casecheck.True(t, bm.Has(5))
+
+	bm.Set(64)
+	bm.Set(100)
+	casecheck.True(t, bm.Has(64))
+	casecheck.False(t, bm.Has(100))
+	casecheck.True(t, bm.TestAndSet(100))
+	casecheck.Equal(t, uint64(2), bm.Count())
+
+	casecheck.True(t, bm.TestAndClear(64))
+	casecheck.False(t, bm.TestAndClear(64))
+	bm.Del(5)
+	casecheck.Equal(t, uint64(0), bm.Count())
+
+	bm.Set(1)
+	bm.Set(99)
+	data, err := bm.MarshalBinary()
+	casecheck.NoError(t, err)
+
+	dense := New()
+	casecheck.NoError(t, dense.UnmarshalBinary(data))
+	casecheck.True(t, dense.Has(1))
+	casecheck.True(t, dense.Has(99))
+	casecheck.Equal(t, uint64(2), dense.Count())
+}
+
+func TestUnit_Atomic_Claim(t *testing.T) {
+	const (
+		size    = 1000
+		workers = 8
+	)
+
+	bm := NewAtomic(size)
+
+	var claimed atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := uint64(0); i < size; i++ {
+				if !bm.TestAndSet(i) {
+					claimed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	casecheck.Equal(t, int64(size), claimed.Load())
+	casecheck.Equal(t, uint64(size), bm.Count())
+}
+
+func Benchmark_Atomic(b *testing.B) {
+	bm := NewAtomic(1 << 20)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var i uint64
+		for pb.Next() {
+			i = (i + 7919) % (1 << 20)
+			bm.TestAndSet(i)
+			bm.TestAndClear(i)
+		}
+	})
+}
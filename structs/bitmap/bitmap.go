@@ -6,19 +6,26 @@
 package bitmap
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
 	"sync"
 )
 
 const (
-	blockSize = 8
+	blockSize = 64
 	MaxIndex  = uint64(1 << 34)
 )
 
+var ErrIndexOutOfRange = errors.New("bitmap index out of range")
+
 type Bitmap struct {
-	bits []byte
+	bits []uint64
 
 	blocks  uint64
 	max     uint64
+	limit   uint64
 	lockoff bool
 
 	mux sync.RWMutex
@@ -38,25 +45,32 @@ func OptMaxIndex(index uint64) Option {
 	}
 }
 
+// OptLimit caps the growth of the bitmap: indexes above the limit are
+// ignored by Set and rejected by TrySet.
+func OptLimit(index uint64) Option {
+	return func(o *Bitmap) {
+		o.limit = min(index, MaxIndex)
+	}
+}
+
 func New(opts ...Option) *Bitmap {
-	bm := &Bitmap{max: 1}
+	bm := &Bitmap{max: 1, limit: MaxIndex}
 
 	for _, opt := range opts {
 		opt(bm)
 	}
 
-	bm.resize(bm.max)
+	bm.resize(min(bm.max, bm.limit))
 
 	return bm
 }
 
 func (b *Bitmap) resize(index uint64) {
-	size := index / blockSize
-	if index-(size%blockSize) > 0 {
-		size++
+	size := index/blockSize + 1
+	if size > b.blocks {
+		b.bits = append(b.bits, make([]uint64, size-b.blocks)...)
 	}
 
-	b.bits = append(b.bits, make([]byte, size-b.blocks)...)
 	b.blocks = uint64(len(b.bits))
 	b.max = b.blocks*blockSize - 1
 }
@@ -65,12 +79,12 @@ func (b *Bitmap) getBlock(index uint64) uint64 {
 	return index / blockSize
 }
 
-func (b *Bitmap) getBit(index uint64) byte {
-	return 1 << (index - b.getBlock(index)*blockSize)
+func (b *Bitmap) getBit(index uint64) uint64 {
+	return 1 << (index % blockSize)
 }
 
 func (b *Bitmap) Set(index uint64) {
-	if index > MaxIndex {
+	if index > b.limit {
 		return
 	}
 
@@ -86,8 +100,18 @@ func (b *Bitmap) Set(index uint64) {
 	b.bits[b.getBlock(index)] |= b.getBit(index)
 }
 
+func (b *Bitmap) TrySet(index uint64) error {
+	if index > b.limit {
+		return fmt.Errorf("%w: %d > %d", ErrIndexOutOfRange, index, b.limit)
+	}
+
+	b.Set(index)
+
+	return nil
+}
+
 func (b *Bitmap) Del(index uint64) {
-	if index > b.max || index > MaxIndex {
+	if index > MaxIndex {
 		return
 	}
 
@@ -96,11 +120,15 @@ func (b *Bitmap) Del(index uint64) {
 		defer b.mux.Unlock()
 	}
 
+	if index > b.max {
+		return
+	}
+
 	b.bits[b.getBlock(index)] &^= b.getBit(index)
 }
 
 func (b *Bitmap) Has(index uint64) bool {
-	if index > b.max || index > MaxIndex {
+	if index > MaxIndex {
 		return false
 	}
 
@@ -109,17 +137,196 @@ func (b *Bitmap) Has(index uint64) bool {
 		defer b.mux.RUnlock()
 	}
 
+	if index > b.max {
+		return false
+	}
+
 	return (b.bits[b.getBlock(index)] & b.getBit(index)) > 0
 }
 
+func (b *Bitmap) Count() uint64 {
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	var count uint64
+	for _, word := range b.bits {
+		count += uint64(bits.OnesCount64(word))
+	}
+
+	return count
+}
+
+// Cap returns the number of indexes available without growing.
+func (b *Bitmap) Cap() uint64 {
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	return b.max + 1
+}
+
+// Len returns the highest set index plus one.
+func (b *Bitmap) Len() uint64 {
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	for block := b.blocks; block > 0; block-- {
+		if word := b.bits[block-1]; word != 0 {
+			return block*blockSize - uint64(bits.LeadingZeros64(word))
+		}
+	}
+
+	return 0
+}
+
+// Shrink releases the trailing empty blocks.
+func (b *Bitmap) Shrink() {
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	size := len(b.bits)
+	for size > 1 && b.bits[size-1] == 0 {
+		size--
+	}
+
+	if size < len(b.bits) || cap(b.bits) > len(b.bits) {
+		b.bits = append(make([]uint64, 0, size), b.bits[:size]...)
+	}
+
+	b.blocks = uint64(len(b.bits))
+	b.max = b.blocks*blockSize - 1
+}
+
+// Reset clears all indexes and keeps the allocated capacity.
+func (b *Bitmap) Reset() {
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	clear(b.bits)
+}
+
+func (b *Bitmap) Any() bool {
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	for _, word := range b.bits {
+		if word != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *Bitmap) None() bool {
+	return !b.Any()
+}
+
+// NextSet returns the first set index greater than or equal to index.
+func (b *Bitmap) NextSet(index uint64) (uint64, bool) {
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	block := b.getBlock(index)
+	if block >= b.blocks {
+		return 0, false
+	}
+
+	if word := b.bits[block] >> (index % blockSize); word != 0 {
+		return index + uint64(bits.TrailingZeros64(word)), true
+	}
+
+	for block++; block < b.blocks; block++ {
+		if word := b.bits[block]; word != 0 {
+			return block*blockSize + uint64(bits.TrailingZeros64(word)), true
+		}
+	}
+
+	return 0, false
+}
+
+// NextClear returns the first clear index greater than or equal to index.
+func (b *Bitmap) NextClear(index uint64) (uint64, bool) {
+	if index > b.limit {
+		return 0, false
+	}
+
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	block := b.getBlock(index)
+	if block >= b.blocks {
+		return index, true
+	}
+
+	if word := ^b.bits[block] >> (index % blockSize); word != 0 {
+		return index + uint64(bits.TrailingZeros64(word)), true
+	}
+
+	for block++; block < b.blocks; block++ {
+		if word := ^b.bits[block]; word != 0 {
+			return block*blockSize + uint64(bits.TrailingZeros64(word)), true
+		}
+	}
+
+	if next := b.blocks * blockSize; next <= b.limit {
+		return next, true
+	}
+
+	return 0, false
+}
+
+// PrevSet returns the last set index less than or equal to index.
+func (b *Bitmap) PrevSet(index uint64) (uint64, bool) {
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	block := b.getBlock(index)
+	if block >= b.blocks {
+		block, index = b.blocks-1, b.max
+	}
+
+	if word := b.bits[block] << (blockSize - 1 - index%blockSize); word != 0 {
+		return index - uint64(bits.LeadingZeros64(word)), true
+	}
+
+	for block > 0 {
+		block--
+		if word := b.bits[block]; word != 0 {
+			return block*blockSize + blockSize - 1 - uint64(bits.LeadingZeros64(word)), true
+		}
+	}
+
+	return 0, false
+}
+
 func (b *Bitmap) MarshalBinary() ([]byte, error) {
 	if !b.lockoff {
 		b.mux.RLock()
 		defer b.mux.RUnlock()
 	}
 
-	out := make([]byte, b.blocks)
-	copy(out, b.bits)
+	out := make([]byte, b.blocks*8)
+	for i, word := range b.bits {
+		binary.LittleEndian.PutUint64(out[i*8:], word)
+	}
 
 	return out, nil
 }
@@ -130,28 +337,57 @@ func (b *Bitmap) UnmarshalBinary(in []byte) error {
 		defer b.mux.Unlock()
 	}
 
-	b.bits = make([]byte, len(in))
-	copy(b.bits, in)
+	b.bits = make([]uint64, max((len(in)+7)/8, 1))
+	for i := range b.bits {
+		var word [8]byte
+		copy(word[:], in[i*8:])
+		b.bits[i] = binary.LittleEndian.Uint64(word[:])
+	}
 
-	b.blocks = uint64(len(in))
-	b.max = b.blocks * blockSize
+	b.blocks = uint64(len(b.bits))
+	b.max = b.blocks*blockSize - 1
 
 	return nil
 }
 
+// CopyTo replaces the bits of dst with the bits of b. Unlike earlier versions
+// the lock setting and the limit of dst are kept: they are read without
+// the lock, so changing them would race with a concurrent user of dst.
 func (b *Bitmap) CopyTo(dst *Bitmap) {
-	if !b.lockoff {
-		b.mux.Lock()
-		defer b.mux.Unlock()
-	}
+	words := b.snapshot()
+
 	if !dst.lockoff {
 		dst.mux.Lock()
 		defer dst.mux.Unlock()
 	}
 
-	dst.bits = make([]byte, len(b.bits))
-	copy(dst.bits, b.bits)
-	dst.blocks = b.blocks
-	dst.max = b.max
-	dst.lockoff = b.lockoff
+	dst.bits = words
+	dst.blocks = uint64(len(words))
+	dst.max = dst.blocks*blockSize - 1
+}
+
+func (b *Bitmap) Clone() *Bitmap {
+	words := b.snapshot()
+
+	return &Bitmap{
+		bits:    words,
+		blocks:  uint64(len(words)),
+		max:     uint64(len(words))*blockSize - 1,
+		limit:   b.limit,
+		lockoff: b.lockoff,
+	}
+}
+
+// snapshot copies the blocks under the read lock only, so that operations
+// between two bitmaps never hold both locks at the same time.
+func (b *Bitmap) snapshot() []uint64 {
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	out := make([]uint64, len(b.bits))
+	copy(out, b.bits)
+
+	return out
 }
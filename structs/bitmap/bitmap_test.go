@@ -6,6 +6,7 @@
 package bitmap
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -25,6 +26,15 @@ func TestUnit_Bitmap_CopyTO(t *testing.T) {
 	casecheck.Equal(t, src.bits, dst.bits)
 	casecheck.Equal(t, src.lockoff, dst.lockoff)
 	casecheck.Equal(t, src.max, dst.max)
+
+	// dst keeps its own settings
+	src = New(OptDisableLock(), OptLimit(1000))
+	src.Set(7)
+	src.CopyTo(dst)
+
+	casecheck.False(t, dst.lockoff)
+	casecheck.Equal(t, MaxIndex, dst.limit)
+	casecheck.True(t, dst.Has(7))
 }
 
 func TestUnit_Bitmap_Resize(t *testing.T) {
@@ -70,6 +80,138 @@ func TestUnit_Bitmap_Marshaling(t *testing.T) {
 
 }
 
+func TestUnit_Bitmap_MarshalingCompatible(t *testing.T) {
+	bm := New()
+	bm.Set(1)
+	bm.Set(5)
+	bm.Set(60)
+
+	b, err := bm.MarshalBinary()
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, []byte{0x22, 0, 0, 0, 0, 0, 0, 0x10}, b)
+
+	casecheck.NoError(t, bm.UnmarshalBinary([]byte{0x22, 0, 0x01}))
+	casecheck.True(t, bm.Has(1))
+	casecheck.True(t, bm.Has(5))
+	casecheck.True(t, bm.Has(16))
+	casecheck.False(t, bm.Has(60))
+	casecheck.False(t, bm.Has(64))
+
+	casecheck.NoError(t, bm.UnmarshalBinary(nil))
+	casecheck.False(t, bm.Has(0))
+	casecheck.True(t, bm.None())
+}
+
+func TestUnit_Bitmap_Scan(t *testing.T) {
+	bm := New()
+
+	_, ok := bm.NextSet(0)
+	casecheck.False(t, ok)
+	_, ok = bm.PrevSet(100)
+	casecheck.False(t, ok)
+	casecheck.True(t, bm.None())
+
+	for _, i := range []uint64{0, 3, 63, 64, 200} {
+		bm.Set(i)
+	}
+
+	casecheck.True(t, bm.Any())
+	casecheck.Equal(t, uint64(5), bm.Count())
+
+	tests := []struct {
+		index  uint64
+		next   uint64
+		nextOk bool
+		clear  uint64
+		prev   uint64
+		prevOk bool
+	}{
+		{index: 0, next: 0, nextOk: true, clear: 1, prev: 0, prevOk: true},
+		{index: 1, next: 3, nextOk: true, clear: 1, prev: 0, prevOk: true},
+		{index: 4, next: 63, nextOk: true, clear: 4, prev: 3, prevOk: true},
+		{index: 63, next: 63, nextOk: true, clear: 65, prev: 63, prevOk: true},
+		{index: 65, next: 200, nextOk: true, clear: 65, prev: 64, prevOk: true},
+		{index: 201, nextOk: false, clear: 201, prev: 200, prevOk: true},
+		{index: 5000, nextOk: false, clear: 5000, prev: 200, prevOk: true},
+	}
+	for _, tt := range tests {
+		next, ok := bm.NextSet(tt.index)
+		casecheck.Equal(t, tt.nextOk, ok, "NextSet(%d)", tt.index)
+		if ok {
+			casecheck.Equal(t, tt.next, next, "NextSet(%d)", tt.index)
+		}
+
+		free, ok := bm.NextClear(tt.index)
+		casecheck.True(t, ok, "NextClear(%d)", tt.index)
+		casecheck.Equal(t, tt.clear, free, "NextClear(%d)", tt.index)
+
+		prev, ok := bm.PrevSet(tt.index)
+		casecheck.Equal(t, tt.prevOk, ok, "PrevSet(%d)", tt.index)
+		if ok {
+			casecheck.Equal(t, tt.prev, prev, "PrevSet(%d)", tt.index)
+		}
+	}
+
+	full := New(OptMaxIndex(127))
+	for i := uint64(0); i < 128; i++ {
+		full.Set(i)
+	}
+	free, ok := full.NextClear(0)
+	casecheck.True(t, ok)
+	casecheck.Equal(t, uint64(128), free)
+}
+
+func TestUnit_Bitmap_Memory(t *testing.T) {
+	bm := New()
+	casecheck.Equal(t, uint64(64), bm.Cap())
+	casecheck.Equal(t, uint64(0), bm.Len())
+
+	bm.Set(3)
+	bm.Set(1000)
+	casecheck.Equal(t, uint64(1024), bm.Cap())
+	casecheck.Equal(t, uint64(1001), bm.Len())
+
+	bm.Del(1000)
+	casecheck.Equal(t, uint64(4), bm.Len())
+	casecheck.Equal(t, uint64(1024), bm.Cap())
+
+	bm.Shrink()
+	casecheck.Equal(t, uint64(64), bm.Cap())
+	casecheck.True(t, bm.Has(3))
+
+	bm.Set(500)
+	bm.Reset()
+	casecheck.True(t, bm.None())
+	casecheck.Equal(t, uint64(512), bm.Cap())
+
+	bm.Shrink()
+	casecheck.Equal(t, uint64(64), bm.Cap())
+}
+
+func TestUnit_Bitmap_Limit(t *testing.T) {
+	bm := New(OptLimit(100))
+
+	casecheck.NoError(t, bm.TrySet(100))
+	err := bm.TrySet(101)
+	casecheck.True(t, errors.Is(err, ErrIndexOutOfRange))
+
+	bm.Set(200)
+	casecheck.False(t, bm.Has(200))
+	casecheck.Equal(t, uint64(101), bm.Len())
+
+	bm.SetRange(0, 1000)
+	casecheck.Equal(t, uint64(101), bm.Count())
+	casecheck.False(t, bm.Flip(150))
+
+	big := New()
+	big.Set(10_000)
+	bm.Or(big)
+	casecheck.Equal(t, uint64(128), bm.Cap())
+
+	err = New().TrySet(MaxIndex + 1)
+	casecheck.True(t, errors.Is(err, ErrIndexOutOfRange))
+}
+
 /*
 goos: linux
 goarch: amd64
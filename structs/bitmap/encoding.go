@@ -0,0 +1,203 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://arxiv.org/abs/0901.3751
+
+package bitmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	encodingVersion = 1
+
+	encodingRaw  = 0
+	encodingEWAH = 1
+)
+
+var (
+	encodingMagic = []byte("OBM")
+
+	ErrInvalidEncoding = errors.New("invalid bitmap encoding")
+)
+
+// WriteTo writes the bitmap with a versioned header. The blocks are stored
+// either raw or compressed in the word-aligned hybrid (EWAH) form,
+// whichever is smaller.
+func (b *Bitmap) WriteTo(w io.Writer) (int64, error) {
+	words := b.snapshot()
+
+	out := make([]byte, 0, len(encodingMagic)+2+binary.MaxVarintLen64)
+	out = append(out, encodingMagic...)
+	out = append(out, encodingVersion, encodingRaw)
+	out = binary.AppendUvarint(out, uint64(len(words)))
+
+	if compressed := encodeEWAH(out, words); len(compressed) < len(out)+len(words)*8 {
+		compressed[len(encodingMagic)+1] = encodingEWAH
+		out = compressed
+	} else {
+		for _, word := range words {
+			out = binary.LittleEndian.AppendUint64(out, word)
+		}
+	}
+
+	n, err := w.Write(out)
+	return int64(n), err
+}
+
+// ReadFrom reads the bitmap written by WriteTo and consumes exactly the encoded bytes.
+// A few bytes of fill markers decode into many blocks, so the decoded size is
+// checked against the limit of b: use OptLimit to read untrusted input.
+func (b *Bitmap) ReadFrom(r io.Reader) (int64, error) {
+	br := &byteReader{r: r}
+
+	head := make([]byte, len(encodingMagic)+2)
+	if _, err := io.ReadFull(br, head); err != nil {
+		return br.n, fmt.Errorf("read header: %w", err)
+	}
+	if !bytes.Equal(head[:len(encodingMagic)], encodingMagic) {
+		return br.n, fmt.Errorf("%w: unknown header", ErrInvalidEncoding)
+	}
+	if head[len(encodingMagic)] != encodingVersion {
+		return br.n, fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, head[len(encodingMagic)])
+	}
+
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return br.n, fmt.Errorf("read blocks count: %w", err)
+	}
+	if count > b.limit/blockSize+1 {
+		return br.n, fmt.Errorf("%w: too many blocks %d for limit %d", ErrInvalidEncoding, count, b.limit)
+	}
+
+	// the slice grows with the decoded data, the announced count alone allocates nothing
+	words := make([]uint64, 0, min(count, 512))
+
+	switch head[len(encodingMagic)+1] {
+	case encodingRaw:
+		words, err = readWords(br, words, count)
+	case encodingEWAH:
+		words, err = decodeEWAH(br, words, count)
+	default:
+		err = fmt.Errorf("%w: unknown encoding %d", ErrInvalidEncoding, head[len(encodingMagic)+1])
+	}
+	if err != nil {
+		return br.n, err
+	}
+	if len(words) == 0 {
+		words = append(words, 0)
+	}
+
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	b.bits = words
+	b.blocks = uint64(len(words))
+	b.max = b.blocks*blockSize - 1
+
+	return br.n, nil
+}
+
+// encodeEWAH appends markers of the form: uvarint(fill length << 1 | fill bit),
+// uvarint(literal count), literal words.
+func encodeEWAH(out []byte, words []uint64) []byte {
+	for i := 0; i < len(words); {
+		var fill uint64
+		var run uint64
+		if words[i] == ^uint64(0) {
+			fill = 1
+		}
+		for i < len(words) && (words[i] == 0 || words[i] == ^uint64(0)) && words[i]&1 == fill {
+			run++
+			i++
+		}
+
+		start := i
+		for i < len(words) && words[i] != 0 && words[i] != ^uint64(0) {
+			i++
+		}
+
+		out = binary.AppendUvarint(out, run<<1|fill)
+		out = binary.AppendUvarint(out, uint64(i-start))
+		for _, word := range words[start:i] {
+			out = binary.LittleEndian.AppendUint64(out, word)
+		}
+	}
+	return out
+}
+
+func decodeEWAH(r *byteReader, words []uint64, count uint64) ([]uint64, error) {
+	for uint64(len(words)) < count {
+		marker, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("read marker: %w", err)
+		}
+		literals, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("read marker: %w", err)
+		}
+
+		run := marker >> 1
+		remaining := count - uint64(len(words))
+		if run > remaining || literals > remaining-run || run+literals == 0 {
+			return nil, fmt.Errorf("%w: marker out of range", ErrInvalidEncoding)
+		}
+
+		var fill uint64
+		if marker&1 == 1 {
+			fill = ^uint64(0)
+		}
+		for j := uint64(0); j < run; j++ {
+			words = append(words, fill)
+		}
+
+		if words, err = readWords(r, words, literals); err != nil {
+			return nil, err
+		}
+	}
+	return words, nil
+}
+
+// readWords appends n little-endian words read from r.
+func readWords(r io.Reader, words []uint64, n uint64) ([]uint64, error) {
+	buf := make([]byte, 8*min(n, 512))
+	for n > 0 {
+		chunk := buf[:8*min(n, 512)]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, fmt.Errorf("read blocks: %w", err)
+		}
+		for i := 0; i < len(chunk); i += 8 {
+			words = append(words, binary.LittleEndian.Uint64(chunk[i:]))
+		}
+		n -= uint64(len(chunk) / 8)
+	}
+	return words, nil
+}
+
+type byteReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *byteReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *byteReader) ReadByte() (byte, error) {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return buf[0], nil
+}
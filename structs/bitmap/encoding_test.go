@@ -0,0 +1,134 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bitmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"slices"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_Bitmap_WriteReadFrom(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func() *Bitmap
+		encoding byte
+		maxSize  int
+	}{
+		{
+			name:     "empty",
+			setup:    func() *Bitmap { return New() },
+			encoding: encodingEWAH,
+			maxSize:  8,
+		},
+		{
+			name:     "sparse",
+			setup:    func() *Bitmap { return newBitmap(1, 1<<20, 1<<24) },
+			encoding: encodingEWAH,
+			maxSize:  64,
+		},
+		{
+			name: "filled runs",
+			setup: func() *Bitmap {
+				bm := New()
+				bm.SetRange(100, 1<<20)
+				bm.Set(1<<21 + 5)
+				return bm
+			},
+			encoding: encodingEWAH,
+			maxSize:  64,
+		},
+		{
+			name: "dense",
+			setup: func() *Bitmap {
+				bm := New()
+				for i := uint64(0); i < 1<<12; i += 3 {
+					bm.Set(i)
+				}
+				return bm
+			},
+			encoding: encodingRaw,
+			maxSize:  1 << 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bm := tt.setup()
+
+			buf := bytes.NewBuffer(nil)
+			n, err := bm.WriteTo(buf)
+			casecheck.NoError(t, err)
+			casecheck.Equal(t, int64(buf.Len()), n)
+			casecheck.True(t, buf.Len() <= tt.maxSize, "encoded size %d", buf.Len())
+			casecheck.Equal(t, tt.encoding, buf.Bytes()[len(encodingMagic)+1])
+
+			// trailing data must stay in the stream
+			buf.WriteString("tail")
+
+			restored := newBitmap(7)
+			m, err := restored.ReadFrom(buf)
+			casecheck.NoError(t, err)
+			casecheck.Equal(t, n, m)
+			casecheck.Equal(t, "tail", buf.String())
+			casecheck.Equal(t, slices.Collect(bm.All()), slices.Collect(restored.All()))
+		})
+	}
+}
+
+func TestUnit_Bitmap_ReadFromInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "header", data: []byte("XXX\x01\x00\x00")},
+		{name: "version", data: []byte("OBM\x09\x00\x00")},
+		{name: "encoding", data: []byte("OBM\x01\x07\x01")},
+		{name: "marker", data: []byte("OBM\x01\x01\x01\x08\x00")},
+		{name: "marker overflow", data: []byte("OBM\x01\x01\x03\x04\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01")},
+		{name: "marker run", data: []byte("OBM\x01\x01\x03\x08\x00")},
+		{name: "too many blocks", data: []byte("OBM\x01\x00\xff\xff\xff\xff\x0f")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New().ReadFrom(bytes.NewReader(tt.data))
+			casecheck.True(t, errors.Is(err, ErrInvalidEncoding), "got %v", err)
+		})
+	}
+
+	_, err := New().ReadFrom(bytes.NewReader([]byte("OBM\x01\x00\x02\x00")))
+	casecheck.Error(t, err)
+
+	// a header announcing the maximum size without the payload must fail before allocating it
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	before := stats.TotalAlloc
+
+	header := binary.AppendUvarint([]byte("OBM\x01\x00"), MaxIndex/blockSize+1)
+	_, err = New().ReadFrom(bytes.NewReader(header))
+	casecheck.Error(t, err)
+
+	runtime.ReadMemStats(&stats)
+	casecheck.True(t, stats.TotalAlloc-before < 1<<20, "allocated %d bytes", stats.TotalAlloc-before)
+
+	// a short fill marker must not expand beyond the limit of the receiver
+	src := New()
+	src.SetRange(0, 1<<20)
+	buf := bytes.NewBuffer(nil)
+	_, err = src.WriteTo(buf)
+	casecheck.NoError(t, err)
+	casecheck.True(t, buf.Len() < 32, "encoded size %d", buf.Len())
+
+	_, err = New(OptLimit(1 << 16)).ReadFrom(bytes.NewReader(buf.Bytes()))
+	casecheck.True(t, errors.Is(err, ErrInvalidEncoding), "got %v", err)
+
+	_, err = New(OptLimit(1 << 20)).ReadFrom(bytes.NewReader(buf.Bytes()))
+	casecheck.NoError(t, err)
+}
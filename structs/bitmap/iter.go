@@ -0,0 +1,123 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bitmap
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// All iterates over the set indexes in ascending order.
+func (b *Bitmap) All() iter.Seq[uint64] {
+	return b.Range(0, MaxIndex+1)
+}
+
+// Range iterates over the set indexes in [from, to).
+// Blocks are read one at a time, so the bitmap may be modified during iteration.
+func (b *Bitmap) Range(from, to uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for block := b.getBlock(from); from < to; block++ {
+			word, ok := b.readBlock(block)
+			if !ok {
+				return
+			}
+
+			word &= rangeMask(from, to, block)
+			for word != 0 {
+				if !yield(block*blockSize + uint64(bits.TrailingZeros64(word))) {
+					return
+				}
+				word &= word - 1
+			}
+
+			from = (block + 1) * blockSize
+		}
+	}
+}
+
+func (b *Bitmap) readBlock(block uint64) (uint64, bool) {
+	if !b.lockoff {
+		b.mux.RLock()
+		defer b.mux.RUnlock()
+	}
+
+	if block >= b.blocks {
+		return 0, false
+	}
+
+	return b.bits[block], true
+}
+
+// SetRange sets all indexes in [from, to).
+func (b *Bitmap) SetRange(from, to uint64) {
+	to = min(to, b.limit+1)
+	if from >= to {
+		return
+	}
+
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	if to-1 > b.max {
+		b.resize(to - 1)
+	}
+
+	for block := b.getBlock(from); block <= b.getBlock(to-1); block++ {
+		b.bits[block] |= rangeMask(from, to, block)
+	}
+}
+
+// ClearRange clears all indexes in [from, to).
+func (b *Bitmap) ClearRange(from, to uint64) {
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	to = min(to, b.max+1)
+	if from >= to {
+		return
+	}
+
+	for block := b.getBlock(from); block <= b.getBlock(to-1); block++ {
+		b.bits[block] &^= rangeMask(from, to, block)
+	}
+}
+
+// Flip inverts the index and returns its new state.
+func (b *Bitmap) Flip(index uint64) bool {
+	if index > b.limit {
+		return false
+	}
+
+	if !b.lockoff {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+	}
+
+	if index > b.max {
+		b.resize(index)
+	}
+
+	b.bits[b.getBlock(index)] ^= b.getBit(index)
+
+	return b.bits[b.getBlock(index)]&b.getBit(index) != 0
+}
+
+// rangeMask returns the bits of the block that fall into [from, to).
+func rangeMask(from, to, block uint64) uint64 {
+	mask := ^uint64(0)
+	start, end := block*blockSize, (block+1)*blockSize
+	if from > start {
+		mask &= ^uint64(0) << (from - start)
+	}
+	if to < end {
+		mask &= ^uint64(0) >> (end - to)
+	}
+	return mask
+}
@@ -0,0 +1,66 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bitmap
+
+import (
+	"slices"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_Bitmap_Iter(t *testing.T) {
+	bm := newBitmap(0, 3, 63, 64, 200)
+
+	casecheck.Equal(t, []uint64{0, 3, 63, 64, 200}, slices.Collect(bm.All()))
+	casecheck.Equal(t, []uint64{3, 63, 64}, slices.Collect(bm.Range(1, 200)))
+	casecheck.Equal(t, []uint64{64, 200}, slices.Collect(bm.Range(64, 1000)))
+	casecheck.Equal(t, []uint64(nil), slices.Collect(bm.Range(201, 1000)))
+	casecheck.Equal(t, []uint64(nil), slices.Collect(bm.Range(5, 5)))
+
+	var got []uint64
+	for i := range bm.All() {
+		got = append(got, i)
+		bm.Del(i)
+		if len(got) == 2 {
+			break
+		}
+	}
+	casecheck.Equal(t, []uint64{0, 3}, got)
+	casecheck.Equal(t, []uint64{63, 64, 200}, slices.Collect(bm.All()))
+}
+
+func TestUnit_Bitmap_Ranges(t *testing.T) {
+	bm := New()
+
+	bm.SetRange(10, 140)
+	casecheck.Equal(t, uint64(130), bm.Count())
+	casecheck.False(t, bm.Has(9))
+	casecheck.True(t, bm.Has(10))
+	casecheck.True(t, bm.Has(139))
+	casecheck.False(t, bm.Has(140))
+
+	bm.ClearRange(20, 128)
+	casecheck.Equal(t, uint64(22), bm.Count())
+	casecheck.True(t, bm.Has(19))
+	casecheck.False(t, bm.Has(20))
+	casecheck.False(t, bm.Has(127))
+	casecheck.True(t, bm.Has(128))
+
+	bm.ClearRange(0, 1<<40)
+	casecheck.True(t, bm.None())
+
+	bm.SetRange(64, 128)
+	all := slices.Collect(bm.All())
+	casecheck.Equal(t, 64, len(all))
+	casecheck.Equal(t, uint64(64), all[0])
+	casecheck.Equal(t, uint64(127), all[63])
+
+	casecheck.True(t, bm.Flip(1000))
+	casecheck.True(t, bm.Has(1000))
+	casecheck.False(t, bm.Flip(1000))
+	casecheck.False(t, bm.Has(1000))
+}
@@ -0,0 +1,124 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://en.wikipedia.org/wiki/Succinct_data_structure
+
+package bitmap
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	superblockWords = 8
+	superblockSize  = superblockWords * blockSize
+	selectSample    = 512
+)
+
+// RankSelect is a frozen read-only copy of a Bitmap with auxiliary indexes:
+// Rank1 runs in O(1) and Select1 in O(log n).
+type RankSelect struct {
+	words []uint64
+	// ranks[i] is the number of set bits before the i-th superblock
+	ranks []uint64
+	// samples[j] is the superblock holding the (j*selectSample)-th set bit
+	samples []uint64
+}
+
+func NewRankSelect(b *Bitmap) *RankSelect {
+	words := b.snapshot()
+	superblocks := (len(words) + superblockWords - 1) / superblockWords
+
+	r := &RankSelect{
+		words: words,
+		ranks: make([]uint64, superblocks+1),
+	}
+
+	var count uint64
+	for sb := 0; sb < superblocks; sb++ {
+		r.ranks[sb] = count
+		for _, word := range words[sb*superblockWords : min((sb+1)*superblockWords, len(words))] {
+			count += uint64(bits.OnesCount64(word))
+		}
+		for uint64(len(r.samples))*selectSample < count {
+			r.samples = append(r.samples, uint64(sb))
+		}
+	}
+	r.ranks[superblocks] = count
+
+	return r
+}
+
+// Len returns the number of indexes covered by the structure.
+func (r *RankSelect) Len() uint64 {
+	return uint64(len(r.words)) * blockSize
+}
+
+func (r *RankSelect) Count() uint64 {
+	return r.ranks[len(r.ranks)-1]
+}
+
+func (r *RankSelect) Has(index uint64) bool {
+	if index >= r.Len() {
+		return false
+	}
+	return r.words[index/blockSize]&(1<<(index%blockSize)) != 0
+}
+
+// Rank1 returns the number of set indexes before index.
+func (r *RankSelect) Rank1(index uint64) uint64 {
+	if index >= r.Len() {
+		return r.Count()
+	}
+
+	block := index / blockSize
+	count := r.ranks[index/superblockSize]
+	for i := block / superblockWords * superblockWords; i < block; i++ {
+		count += uint64(bits.OnesCount64(r.words[i]))
+	}
+
+	return count + uint64(bits.OnesCount64(r.words[block]&(1<<(index%blockSize)-1)))
+}
+
+// Rank0 returns the number of clear indexes before index.
+func (r *RankSelect) Rank0(index uint64) uint64 {
+	index = min(index, r.Len())
+	return index - r.Rank1(index)
+}
+
+// Select1 returns the position of the k-th set index, counting from zero.
+func (r *RankSelect) Select1(k uint64) (uint64, bool) {
+	if k >= r.Count() {
+		return 0, false
+	}
+
+	lo := r.samples[k/selectSample]
+	hi := uint64(len(r.ranks) - 1)
+	if next := k/selectSample + 1; next < uint64(len(r.samples)) {
+		hi = r.samples[next] + 1
+	}
+
+	sb := lo + uint64(sort.Search(int(hi-lo), func(i int) bool {
+		return r.ranks[lo+uint64(i)+1] > k
+	}))
+
+	k -= r.ranks[sb]
+	for block := sb * superblockWords; ; block++ {
+		word := r.words[block]
+		count := uint64(bits.OnesCount64(word))
+		if k < count {
+			return block*blockSize + selectInWord(word, k), true
+		}
+		k -= count
+	}
+}
+
+func selectInWord(word, k uint64) uint64 {
+	for ; k > 0; k-- {
+		word &= word - 1
+	}
+	return uint64(bits.TrailingZeros64(word))
+}
@@ -0,0 +1,78 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bitmap
+
+import (
+	"math/rand"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_RankSelect(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	bm := New()
+	for i := uint64(0); i < 20_000; i++ {
+		// dense and sparse regions
+		if (i < 5_000 && rnd.Intn(2) == 0) || rnd.Intn(50) == 0 {
+			bm.Set(i)
+		}
+	}
+
+	rs := NewRankSelect(bm)
+	casecheck.Equal(t, bm.Count(), rs.Count())
+
+	var rank uint64
+	for i := uint64(0); i < rs.Len(); i++ {
+		casecheck.Equal(t, rank, rs.Rank1(i), "Rank1(%d)", i)
+		casecheck.Equal(t, i-rank, rs.Rank0(i), "Rank0(%d)", i)
+
+		if bm.Has(i) {
+			casecheck.True(t, rs.Has(i))
+
+			pos, ok := rs.Select1(rank)
+			casecheck.True(t, ok, "Select1(%d)", rank)
+			casecheck.Equal(t, i, pos, "Select1(%d)", rank)
+
+			rank++
+		}
+	}
+
+	casecheck.Equal(t, rs.Count(), rs.Rank1(rs.Len()+100))
+	_, ok := rs.Select1(rs.Count())
+	casecheck.False(t, ok)
+
+	// the frozen copy does not follow the source
+	bm.Set(1 << 20)
+	casecheck.False(t, rs.Has(1<<20))
+}
+
+func TestUnit_RankSelect_Empty(t *testing.T) {
+	rs := NewRankSelect(New())
+
+	casecheck.Equal(t, uint64(0), rs.Count())
+	casecheck.Equal(t, uint64(0), rs.Rank1(10))
+	_, ok := rs.Select1(0)
+	casecheck.False(t, ok)
+}
+
+func Benchmark_RankSelect(b *testing.B) {
+	bm := New()
+	for i := uint64(0); i < 1<<20; i += 3 {
+		bm.Set(i)
+	}
+	rs := NewRankSelect(bm)
+	count := rs.Count()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		rs.Rank1(uint64(i) % (1 << 20))
+		rs.Select1(uint64(i) % count)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://www.cs.amherst.edu/~ccmcgeoch/cs34/papers/cacheefficientbloomfilters-jea.pdf
+
+// Блочный фильтр Блума размещает все биты одного ключа в одном 64-байтном блоке,
+// поэтому проверка ключа затрагивает ровно одну кэш-линию вместо k случайных.
+// Платой за это является немного более высокий процент ложных срабатываний
+// по сравнению с классическим фильтром того же размера.
+
+package bloom
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+	"sync"
+)
+
+const (
+	blockWords = 8
+	blockBits  = blockWords * 64
+)
+
+type Blocked struct {
+	blocks [][blockWords]uint64
+	k      uint32
+	salt   [saltSize]byte
+
+	optSize uint64
+	optRate float64
+
+	pool *sync.Pool
+	mux  sync.RWMutex
+}
+
+func NewBlocked(opts ...Option) (*Blocked, error) {
+	conf, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m, k := calcOptimalParams(conf.optSize, conf.optRate)
+
+	salts, err := newSalts(1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Blocked{
+		blocks:  make([][blockWords]uint64, (m+blockBits-1)/blockBits),
+		k:       uint32(k),
+		salt:    salts[0],
+		optSize: conf.optSize,
+		optRate: conf.optRate,
+		pool:    conf.pool,
+	}, nil
+}
+
+func (b *Blocked) Add(arg any) {
+	key := hashKey(b.pool, anyToBytes(arg), b.salt[:])
+	index, h1, h2 := b.locate(key)
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	block := &b.blocks[index]
+	for i := uint32(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % blockBits
+		block[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (b *Blocked) Contain(arg any) bool {
+	key := hashKey(b.pool, anyToBytes(arg), b.salt[:])
+	index, h1, h2 := b.locate(key)
+
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	block := &b.blocks[index]
+	for i := uint32(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % blockBits
+		if block[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// locate picks the block by the high bits of the key and derives
+// the in-block positions from a remixed key to avoid correlation.
+func (b *Blocked) locate(key uint64) (uint64, uint32, uint32) {
+	index, _ := bits.Mul64(key, uint64(len(b.blocks)))
+	mixed := mix64(key)
+	return index, uint32(mixed), uint32(mixed>>32) | 1
+}
+
+func hashKey(pool *sync.Pool, val, salt []byte) uint64 {
+	h, ok := pool.Get().(hash.Hash)
+	if !ok {
+		panic("failed get hash function from pool")
+	}
+	defer func() {
+		pool.Put(h)
+	}()
+
+	h.Reset()
+	h.Write(val)
+	h.Write(salt)
+
+	if h64, ok := h.(hash.Hash64); ok {
+		return h64.Sum64()
+	}
+	return binary.BigEndian.Uint64(h.Sum(nil))
+}
+
+// mix64 is the splitmix64 finalizer.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
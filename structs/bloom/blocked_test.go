@@ -0,0 +1,144 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bloom
+
+import (
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+type filter interface {
+	Add(arg any)
+	Contain(arg any) bool
+}
+
+func falsePositiveRate(f filter, size int) float64 {
+	for i := 0; i < size; i++ {
+		f.Add(i)
+	}
+
+	fp := 0
+	for i := size; i < size*11; i++ {
+		if f.Contain(i) {
+			fp++
+		}
+	}
+	return float64(fp) / float64(size*10)
+}
+
+func TestUnit_Blocked(t *testing.T) {
+	bf, err := NewBlocked(Quantity(4, 0.01))
+	casecheck.NoError(t, err)
+
+	bf.Add("hello")
+	bf.Add("user")
+	bf.Add("home")
+
+	casecheck.False(t, bf.Contain("users"))
+	casecheck.True(t, bf.Contain("user"))
+	casecheck.True(t, bf.Contain("hello"))
+	casecheck.True(t, bf.Contain("home"))
+
+	_, err = NewBlocked(Quantity(0, 0.01))
+	casecheck.Error(t, err)
+
+	_, err = NewBlocked(Quantity(1, 1))
+	casecheck.Error(t, err)
+}
+
+func TestUnit_SplitBlock(t *testing.T) {
+	bf, err := NewSplitBlock(Quantity(4, 0.01))
+	casecheck.NoError(t, err)
+
+	bf.Add("hello")
+	bf.Add("user")
+	bf.Add("home")
+
+	casecheck.True(t, bf.Contain("user"))
+	casecheck.True(t, bf.Contain("hello"))
+	casecheck.True(t, bf.Contain("home"))
+
+	_, err = NewSplitBlock(Quantity(0, 0.01))
+	casecheck.Error(t, err)
+}
+
+func TestUnit_FalsePositiveRate(t *testing.T) {
+	const (
+		size = 100_000
+		rate = 0.01
+	)
+
+	classic, err := New(Quantity(size, rate))
+	casecheck.NoError(t, err)
+	blocked, err := NewBlocked(Quantity(size, rate))
+	casecheck.NoError(t, err)
+	split, err := NewSplitBlock(Quantity(size, rate))
+	casecheck.NoError(t, err)
+
+	tests := []struct {
+		name string
+		f    filter
+		max  float64
+	}{
+		{name: "classic", f: classic, max: rate * 1.5},
+		{name: "blocked", f: blocked, max: rate * 2},
+		{name: "split block", f: split, max: rate * 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := falsePositiveRate(tt.f, size)
+			casecheck.True(t, got < tt.max, "false positive rate %f, want < %f", got, tt.max)
+		})
+	}
+}
+
+func runFilterContain(b *testing.B, f filter) {
+	for i := 0; i < vSize/10; i++ {
+		f.Add(i)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		f.Contain(i)
+	}
+}
+
+/*
+goos: linux
+goarch: amd64
+pkg: go.osspkg.com/algorithms/structs/bloom
+cpu: Intel(R) Xeon(R) Processor
+Benchmark_Contain_Classic    	 2414479	       485.2 ns/op	      34 B/op	       4 allocs/op
+Benchmark_Contain_Blocked    	10589467	       191.0 ns/op	      16 B/op	       1 allocs/op
+Benchmark_Contain_SplitBlock 	10666983	       190.7 ns/op	      16 B/op	       1 allocs/op
+*/
+
+func Benchmark_Contain_Classic(b *testing.B) {
+	bf, err := New(Quantity(vSize, vRate))
+	if err != nil {
+		b.FailNow()
+	}
+	runFilterContain(b, bf)
+}
+
+func Benchmark_Contain_Blocked(b *testing.B) {
+	bf, err := NewBlocked(Quantity(vSize, vRate))
+	if err != nil {
+		b.FailNow()
+	}
+	runFilterContain(b, bf)
+}
+
+func Benchmark_Contain_SplitBlock(b *testing.B) {
+	bf, err := NewSplitBlock(Quantity(vSize, vRate))
+	if err != nil {
+		b.FailNow()
+	}
+	runFilterContain(b, bf)
+}
@@ -37,13 +37,19 @@ import (
 
 const saltSize = 8
 
+var (
+	headerV1 = []byte("OSSPkg:bloom")
+	headerV2 = []byte("OSSPkg:bloom:v2")
+)
+
 type Bloom struct {
 	bits  *bitmap.Bitmap
 	size  uint64
 	salts [][saltSize]byte
 
-	optSize uint64
-	optRate float64
+	optSize     uint64
+	optRate     float64
+	optCompress bool
 
 	pool *sync.Pool
 	mux  sync.RWMutex
@@ -64,7 +70,33 @@ func Quantity(size uint64, rate float64) Option {
 	}
 }
 
+// CompressDump makes Dump write the compressed bitmap encoding, which keeps
+// sparse filters small. Versions before it can't restore such dumps,
+// so by default Dump writes the original format.
+func CompressDump() Option {
+	return func(b *Bloom) {
+		b.optCompress = true
+	}
+}
+
 func New(opts ...Option) (*Bloom, error) {
+	b, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m, k := calcOptimalParams(b.optSize, b.optRate)
+
+	b.size = m
+	b.bits = bitmap.New(bitmap.OptMaxIndex(m), bitmap.OptDisableLock())
+	if b.salts, err = newSalts(int(k)); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func applyOptions(opts []Option) (*Bloom, error) {
 	b := &Bloom{
 		optSize: 10_000_000,
 		optRate: 0.1,
@@ -82,21 +114,21 @@ func New(opts ...Option) (*Bloom, error) {
 		return nil, fmt.Errorf("false positive rate must be between 0.0 and 1.0")
 	}
 
-	m, k := calcOptimalParams(b.optSize, b.optRate)
+	return b, nil
+}
 
-	b.size = m
-	b.bits = bitmap.New(bitmap.OptMaxIndex(m), bitmap.OptDisableLock())
-	b.salts = make([][saltSize]byte, k)
+func newSalts(count int) ([][saltSize]byte, error) {
+	salts := make([][saltSize]byte, count)
 
-	for i := 0; i < int(k); i++ {
-		if _, err := rand.Read(b.salts[i][:]); err != nil {
+	for i := 0; i < count; i++ {
+		if _, err := rand.Read(salts[i][:]); err != nil {
 			return nil, fmt.Errorf("generate hash salt: %w", err)
 		}
 
-		b.salts[i] = [saltSize]byte(bytes.ReplaceAll(b.salts[i][:], []byte("\n"), []byte("~")))
+		salts[i] = [saltSize]byte(bytes.ReplaceAll(salts[i][:], []byte("\n"), []byte("~")))
 	}
 
-	return b, nil
+	return salts, nil
 }
 
 func (b *Bloom) CopyTo(dst *Bloom) {
@@ -114,13 +146,26 @@ func (b *Bloom) CopyTo(dst *Bloom) {
 
 	dst.optSize = b.optSize
 	dst.optRate = b.optRate
+	dst.optCompress = b.optCompress
+}
+
+func (b *Bloom) Reset() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.bits.Reset()
 }
 
 func (b *Bloom) Dump(w io.Writer) error {
 	b.mux.RLock()
 	defer b.mux.RUnlock()
 
-	if _, err := w.Write([]byte("OSSPkg:bloom\n")); err != nil {
+	header := headerV1
+	if b.optCompress {
+		header = headerV2
+	}
+
+	if _, err := fmt.Fprintf(w, "%s\n", header); err != nil {
 		return fmt.Errorf("write header: %w", err)
 	}
 
@@ -138,6 +183,13 @@ func (b *Bloom) Dump(w io.Writer) error {
 		}
 	}
 
+	if b.optCompress {
+		if _, err := b.bits.WriteTo(w); err != nil {
+			return fmt.Errorf("write bitmap: %w", err)
+		}
+		return nil
+	}
+
 	bb, err := b.bits.MarshalBinary()
 	if err != nil {
 		return fmt.Errorf("marshal bitset: %w", err)
@@ -160,7 +212,8 @@ func (b *Bloom) Restore(r io.Reader) error {
 	if err != nil {
 		return fmt.Errorf("read header: %w", err)
 	}
-	if !bytes.Equal(head[:len(head)-1], []byte("OSSPkg:bloom")) {
+	head = head[:len(head)-1]
+	if !bytes.Equal(head, headerV1) && !bytes.Equal(head, headerV2) {
 		return fmt.Errorf("invalid header")
 	}
 
@@ -194,6 +247,13 @@ func (b *Bloom) Restore(r io.Reader) error {
 		b.salts[i] = [saltSize]byte(salt)
 	}
 
+	if bytes.Equal(head, headerV2) {
+		if _, err = b.bits.ReadFrom(reader); err != nil {
+			return fmt.Errorf("read bitmap: %w", err)
+		}
+		return nil
+	}
+
 	bm, err := io.ReadAll(reader)
 	if err != nil {
 		return fmt.Errorf("read bitmap: %w", err)
@@ -36,6 +36,7 @@ func TestUnit_Bloom(t *testing.T) {
 	buf := bytes.NewBuffer(nil)
 	casecheck.NoError(t, bf.Dump(buf))
 	b1 := buf.Bytes()
+	casecheck.True(t, bytes.HasPrefix(b1, []byte("OSSPkg:bloom\n")))
 
 	fmt.Println(string(b1))
 
@@ -52,6 +53,48 @@ func TestUnit_Bloom(t *testing.T) {
 	casecheck.True(t, bf.Contain("home"))
 }
 
+func TestUnit_BloomRestoreLegacy(t *testing.T) {
+	bf, err := New(Quantity(100, 0.01))
+	casecheck.NoError(t, err)
+
+	bf.Add("hello")
+	bf.Add("user")
+
+	bits, err := bf.bits.MarshalBinary()
+	casecheck.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	fmt.Fprintf(buf, "OSSPkg:bloom\n%d\n", len(bf.salts))
+	for _, salt := range bf.salts {
+		buf.Write(salt[:])
+		buf.WriteString("\n")
+	}
+	buf.Write(bits)
+
+	restored, err := New(Quantity(100, 0.01))
+	casecheck.NoError(t, err)
+	casecheck.NoError(t, restored.Restore(buf))
+
+	casecheck.True(t, restored.Contain("hello"))
+	casecheck.True(t, restored.Contain("user"))
+	casecheck.False(t, restored.Contain("users"))
+}
+
+func TestUnit_BloomDumpSparse(t *testing.T) {
+	bf, err := New(Quantity(1_000_000, 0.01), CompressDump())
+	casecheck.NoError(t, err)
+
+	bf.Add("hello")
+
+	buf := bytes.NewBuffer(nil)
+	casecheck.NoError(t, bf.Dump(buf))
+	casecheck.True(t, bytes.HasPrefix(buf.Bytes(), []byte("OSSPkg:bloom:v2\n")))
+	casecheck.True(t, buf.Len() < 1024, "dump size %d", buf.Len())
+
+	casecheck.NoError(t, bf.Restore(buf))
+	casecheck.True(t, bf.Contain("hello"))
+}
+
 func TestUnit_Bloom2(t *testing.T) {
 	_, err := New(Quantity(0, 0.00001))
 	casecheck.Error(t, err)
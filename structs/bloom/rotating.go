@@ -0,0 +1,141 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// Вращающийся фильтр Блума хранит несколько поколений классических фильтров.
+// Новые элементы добавляются в текущее поколение, а проверка выполняется по всем.
+// При ротации самое старое поколение очищается и становится текущим, что дает
+// приближенную семантику "встречался за последнее окно времени":
+// при G поколениях и периоде P элемент помнится от (G-1)*P до G*P.
+
+package bloom
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Rotating struct {
+	gens []*Bloom
+
+	generations int
+	period      time.Duration
+	clock       func() time.Time
+	last        time.Time
+	bloomOpts   []Option
+
+	mux sync.Mutex
+}
+
+type RotatingOption func(r *Rotating)
+
+func Generations(count int) RotatingOption {
+	return func(r *Rotating) {
+		r.generations = count
+	}
+}
+
+func Period(d time.Duration) RotatingOption {
+	return func(r *Rotating) {
+		r.period = d
+	}
+}
+
+func Clock(now func() time.Time) RotatingOption {
+	return func(r *Rotating) {
+		r.clock = now
+	}
+}
+
+func BloomOptions(opts ...Option) RotatingOption {
+	return func(r *Rotating) {
+		r.bloomOpts = append(r.bloomOpts, opts...)
+	}
+}
+
+func NewRotating(opts ...RotatingOption) (*Rotating, error) {
+	r := &Rotating{
+		generations: 2,
+		clock:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.generations < 1 {
+		return nil, fmt.Errorf("generations count must be greater than 0")
+	}
+	if r.period < 0 {
+		return nil, fmt.Errorf("rotation period cannot be negative")
+	}
+
+	r.gens = make([]*Bloom, r.generations)
+	for i := range r.gens {
+		b, err := New(r.bloomOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("create generation %d: %w", i, err)
+		}
+		r.gens[i] = b
+	}
+
+	r.last = r.clock()
+
+	return r, nil
+}
+
+func (r *Rotating) Add(arg any) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.tick()
+	r.gens[0].Add(arg)
+}
+
+func (r *Rotating) Contain(arg any) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.tick()
+	for _, b := range r.gens {
+		if b.Contain(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rotating) Rotate() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.rotate()
+	r.last = r.clock()
+}
+
+func (r *Rotating) rotate() {
+	oldest := r.gens[len(r.gens)-1]
+	oldest.Reset()
+
+	copy(r.gens[1:], r.gens[:len(r.gens)-1])
+	r.gens[0] = oldest
+}
+
+func (r *Rotating) tick() {
+	if r.period == 0 {
+		return
+	}
+
+	elapsed := r.clock().Sub(r.last)
+	if elapsed < r.period {
+		return
+	}
+
+	steps := min(int64(elapsed/r.period), int64(len(r.gens)))
+	for i := int64(0); i < steps; i++ {
+		r.rotate()
+	}
+	r.last = r.last.Add(elapsed - elapsed%r.period)
+}
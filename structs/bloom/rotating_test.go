@@ -0,0 +1,76 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bloom
+
+import (
+	"testing"
+	"time"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_Rotating(t *testing.T) {
+	rf, err := NewRotating(Generations(3), BloomOptions(Quantity(100, 0.01)))
+	casecheck.NoError(t, err)
+
+	rf.Add("a")
+	casecheck.True(t, rf.Contain("a"))
+
+	rf.Rotate()
+	rf.Add("b")
+	casecheck.True(t, rf.Contain("a"))
+	casecheck.True(t, rf.Contain("b"))
+
+	rf.Rotate()
+	casecheck.True(t, rf.Contain("a"))
+	casecheck.True(t, rf.Contain("b"))
+
+	rf.Rotate()
+	casecheck.False(t, rf.Contain("a"))
+	casecheck.True(t, rf.Contain("b"))
+
+	rf.Rotate()
+	casecheck.False(t, rf.Contain("a"))
+	casecheck.False(t, rf.Contain("b"))
+
+	_, err = NewRotating(Generations(0))
+	casecheck.Error(t, err)
+
+	_, err = NewRotating(BloomOptions(Quantity(0, 0.01)))
+	casecheck.Error(t, err)
+}
+
+func TestUnit_RotatingPeriod(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	rf, err := NewRotating(
+		Generations(2),
+		Period(time.Minute),
+		Clock(clock),
+		BloomOptions(Quantity(100, 0.01)),
+	)
+	casecheck.NoError(t, err)
+
+	rf.Add("a")
+
+	now = now.Add(59 * time.Second)
+	casecheck.True(t, rf.Contain("a"))
+
+	now = now.Add(time.Second)
+	rf.Add("b")
+	casecheck.True(t, rf.Contain("a"))
+	casecheck.True(t, rf.Contain("b"))
+
+	now = now.Add(time.Minute)
+	casecheck.False(t, rf.Contain("a"))
+	casecheck.True(t, rf.Contain("b"))
+
+	rf.Add("c")
+	now = now.Add(time.Hour)
+	casecheck.False(t, rf.Contain("b"))
+	casecheck.False(t, rf.Contain("c"))
+}
@@ -0,0 +1,100 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://github.com/apache/parquet-format/blob/master/BloomFilter.md
+
+// Фильтр Блума с разделенными блоками (split block) в раскладке Parquet/Impala:
+// блок из 256 бит состоит из восьми 32-битных слов, и в каждом слове
+// для ключа устанавливается ровно один бит.
+
+package bloom
+
+import (
+	"math"
+	"sync"
+)
+
+const splitBlockWords = 8
+
+var splitBlockSalts = [splitBlockWords]uint32{
+	0x47b6137b, 0x44974d91, 0x8824ad5b, 0xa2b7289d,
+	0x705495c7, 0x2df1424b, 0x9efc4947, 0x5c6bfb31,
+}
+
+type SplitBlock struct {
+	blocks [][splitBlockWords]uint32
+	salt   [saltSize]byte
+
+	optSize uint64
+	optRate float64
+
+	pool *sync.Pool
+	mux  sync.RWMutex
+}
+
+func NewSplitBlock(opts ...Option) (*SplitBlock, error) {
+	conf, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	salts, err := newSalts(1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SplitBlock{
+		blocks:  make([][splitBlockWords]uint32, calcSplitBlocks(conf.optSize, conf.optRate)),
+		salt:    salts[0],
+		optSize: conf.optSize,
+		optRate: conf.optRate,
+		pool:    conf.pool,
+	}, nil
+}
+
+func (b *SplitBlock) Add(arg any) {
+	key := hashKey(b.pool, anyToBytes(arg), b.salt[:])
+	index := b.locate(key)
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	block := &b.blocks[index]
+	for i, salt := range splitBlockSalts {
+		block[i] |= 1 << ((uint32(key) * salt) >> 27)
+	}
+}
+
+func (b *SplitBlock) Contain(arg any) bool {
+	key := hashKey(b.pool, anyToBytes(arg), b.salt[:])
+	index := b.locate(key)
+
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+
+	block := &b.blocks[index]
+	for i, salt := range splitBlockSalts {
+		if block[i]&(1<<((uint32(key)*salt)>>27)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *SplitBlock) locate(key uint64) uint64 {
+	return ((key >> 32) * uint64(len(b.blocks))) >> 32
+}
+
+func calcSplitBlocks(n uint64, p float64) uint64 {
+	m := -splitBlockWords * float64(n) / math.Log(1-math.Pow(p, 1.0/splitBlockWords))
+	blocks := uint64(math.Ceil(m / (splitBlockWords * 32)))
+	if blocks < 1 {
+		blocks = 1
+	}
+	if blocks > math.MaxUint32 {
+		blocks = math.MaxUint32
+	}
+	return blocks
+}
@@ -0,0 +1,153 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://dl.acm.org/doi/10.1145/253262.253268
+
+// Bit-sliced index (BSI) хранит целочисленный атрибут строк в виде набора
+// битовых срезов: i-й срез содержит строки, у которых установлен i-й бит значения.
+// Сравнения и суммирование выполняются операциями над срезами без перебора строк.
+// Структура не потокобезопасна.
+
+package bsi
+
+import (
+	"errors"
+	"math/bits"
+
+	"go.osspkg.com/algorithms/structs/roaring"
+)
+
+var ErrSumOverflow = errors.New("sum overflows uint64")
+
+type BSI struct {
+	exists *roaring.Bitmap
+	slices []*roaring.Bitmap
+}
+
+func New() *BSI {
+	return &BSI{
+		exists: roaring.New(),
+	}
+}
+
+func (b *BSI) Set(row uint32, value uint64) {
+	for len(b.slices) < bits.Len64(value) {
+		b.slices = append(b.slices, roaring.New())
+	}
+
+	b.exists.Add(row)
+	for i, slice := range b.slices {
+		if value&(1<<i) != 0 {
+			slice.Add(row)
+		} else {
+			slice.Remove(row)
+		}
+	}
+}
+
+func (b *BSI) Get(row uint32) (uint64, bool) {
+	if !b.exists.Contains(row) {
+		return 0, false
+	}
+
+	var value uint64
+	for i, slice := range b.slices {
+		if slice.Contains(row) {
+			value |= 1 << i
+		}
+	}
+	return value, true
+}
+
+func (b *BSI) Delete(row uint32) {
+	b.exists.Remove(row)
+	for _, slice := range b.slices {
+		slice.Remove(row)
+	}
+}
+
+// Rows returns the rows that have a value.
+func (b *BSI) Rows() *roaring.Bitmap {
+	return b.exists.Clone()
+}
+
+func (b *BSI) RangeEQ(value uint64) *roaring.Bitmap {
+	_, eq, _ := b.compare(value)
+	return eq
+}
+
+func (b *BSI) RangeLT(value uint64) *roaring.Bitmap {
+	lt, _, _ := b.compare(value)
+	return lt
+}
+
+func (b *BSI) RangeLE(value uint64) *roaring.Bitmap {
+	lt, eq, _ := b.compare(value)
+	lt.Or(eq)
+	return lt
+}
+
+func (b *BSI) RangeGT(value uint64) *roaring.Bitmap {
+	_, _, gt := b.compare(value)
+	return gt
+}
+
+func (b *BSI) RangeGE(value uint64) *roaring.Bitmap {
+	_, eq, gt := b.compare(value)
+	gt.Or(eq)
+	return gt
+}
+
+// RangeBetween returns the rows with from <= value <= to.
+func (b *BSI) RangeBetween(from, to uint64) *roaring.Bitmap {
+	if from > to {
+		return roaring.New()
+	}
+	result := b.RangeGE(from)
+	result.And(b.RangeLE(to))
+	return result
+}
+
+// Sum returns the sum and the number of values in the filtered rows.
+// A nil filter selects all rows. ErrSumOverflow is returned together with
+// the count when the sum does not fit into uint64.
+func (b *BSI) Sum(filter *roaring.Bitmap) (uint64, uint64, error) {
+	rows := b.exists
+	if filter != nil {
+		rows = roaring.And(b.exists, filter)
+	}
+
+	var sum, carry uint64
+	for i, slice := range b.slices {
+		card := roaring.And(slice, rows).Cardinality()
+		if i > 0 && card>>(64-i) != 0 {
+			return 0, rows.Cardinality(), ErrSumOverflow
+		}
+		if sum, carry = bits.Add64(sum, card<<i, 0); carry != 0 {
+			return 0, rows.Cardinality(), ErrSumOverflow
+		}
+	}
+	return sum, rows.Cardinality(), nil
+}
+
+// compare splits the rows into less, equal and greater than the value
+// walking the slices from the most significant bit (O'Neil & Quass).
+func (b *BSI) compare(value uint64) (*roaring.Bitmap, *roaring.Bitmap, *roaring.Bitmap) {
+	if bits.Len64(value) > len(b.slices) {
+		return b.exists.Clone(), roaring.New(), roaring.New()
+	}
+
+	lt, eq, gt := roaring.New(), b.exists.Clone(), roaring.New()
+	for i := len(b.slices) - 1; i >= 0; i-- {
+		if value&(1<<i) != 0 {
+			lt.Or(roaring.AndNot(eq, b.slices[i]))
+			eq.And(b.slices[i])
+		} else {
+			gt.Or(roaring.And(eq, b.slices[i]))
+			eq.AndNot(b.slices[i])
+		}
+	}
+	return lt, eq, gt
+}
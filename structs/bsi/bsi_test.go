@@ -0,0 +1,128 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package bsi
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"go.osspkg.com/algorithms/structs/roaring"
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_BSI(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	index := New()
+	values := make(map[uint32]uint64)
+	for i := 0; i < 5_000; i++ {
+		row := uint32(rnd.Intn(100_000))
+		value := uint64(rnd.Intn(1_000))
+		index.Set(row, value)
+		values[row] = value
+	}
+	for row := range values {
+		if rnd.Intn(10) == 0 {
+			index.Delete(row)
+			delete(values, row)
+		}
+	}
+
+	filter := func(fn func(v uint64) bool) []uint32 {
+		result := roaring.New()
+		for row, v := range values {
+			if fn(v) {
+				result.Add(row)
+			}
+		}
+		return result.ToArray()
+	}
+
+	casecheck.Equal(t, uint64(len(values)), index.Rows().Cardinality())
+
+	for _, v := range []uint64{0, 1, 255, 256, 500, 999, 1_000, 1 << 40} {
+		casecheck.Equal(t, filter(func(x uint64) bool { return x == v }), index.RangeEQ(v).ToArray(), "EQ %d", v)
+		casecheck.Equal(t, filter(func(x uint64) bool { return x < v }), index.RangeLT(v).ToArray(), "LT %d", v)
+		casecheck.Equal(t, filter(func(x uint64) bool { return x <= v }), index.RangeLE(v).ToArray(), "LE %d", v)
+		casecheck.Equal(t, filter(func(x uint64) bool { return x > v }), index.RangeGT(v).ToArray(), "GT %d", v)
+		casecheck.Equal(t, filter(func(x uint64) bool { return x >= v }), index.RangeGE(v).ToArray(), "GE %d", v)
+	}
+
+	casecheck.Equal(t, filter(func(x uint64) bool { return x >= 100 && x <= 200 }), index.RangeBetween(100, 200).ToArray())
+	casecheck.True(t, index.RangeBetween(200, 100).IsEmpty())
+
+	for row, value := range values {
+		got, ok := index.Get(row)
+		casecheck.True(t, ok)
+		casecheck.Equal(t, value, got)
+	}
+
+	var sum, count uint64
+	rows := roaring.New()
+	for row, value := range values {
+		if value%2 == 0 {
+			sum += value
+			count++
+			rows.Add(row)
+		}
+	}
+	rows.Add(100_001)
+
+	gotSum, gotCount, err := index.Sum(rows)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, sum, gotSum)
+	casecheck.Equal(t, count, gotCount)
+
+	sum = 0
+	for _, value := range values {
+		sum += value
+	}
+	gotSum, gotCount, err = index.Sum(nil)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, sum, gotSum)
+	casecheck.Equal(t, uint64(len(values)), gotCount)
+}
+
+func TestUnit_BSI_SumOverflow(t *testing.T) {
+	index := New()
+	index.Set(1, math.MaxUint64-1)
+	index.Set(2, 1)
+
+	sum, count, err := index.Sum(nil)
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, uint64(math.MaxUint64), sum)
+	casecheck.Equal(t, uint64(2), count)
+
+	// the carry of the additions
+	index.Set(3, 1)
+	_, count, err = index.Sum(nil)
+	casecheck.True(t, errors.Is(err, ErrSumOverflow))
+	casecheck.Equal(t, uint64(3), count)
+
+	// the shift of the highest slice
+	index.Set(3, 1<<63)
+	index.Set(4, 1<<63)
+	_, _, err = index.Sum(nil)
+	casecheck.True(t, errors.Is(err, ErrSumOverflow))
+}
+
+func TestUnit_BSI_Overwrite(t *testing.T) {
+	index := New()
+	index.Set(1, 7)
+	index.Set(1, 2)
+
+	v, ok := index.Get(1)
+	casecheck.True(t, ok)
+	casecheck.Equal(t, uint64(2), v)
+
+	_, ok = index.Get(2)
+	casecheck.False(t, ok)
+
+	casecheck.Equal(t, []uint32{1}, index.RangeEQ(2).ToArray())
+	casecheck.True(t, index.RangeEQ(7).IsEmpty())
+}
@@ -0,0 +1,423 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://en.wikipedia.org/wiki/Count%E2%80%93min_sketch
+
+// Count-Min Sketch - вероятностная структура для оценки частоты элементов потока.
+// Оценка никогда не бывает меньше реальной частоты и с вероятностью 1-delta
+// превышает ее не более чем на epsilon * N, где N - сумма всех добавлений.
+// Консервативное обновление увеличивает только минимальные счетчики,
+// что заметно уменьшает переоценку.
+
+package cms
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	saltSize     = 8
+	restoreChunk = 1 << 12
+)
+
+type CMS struct {
+	width  uint64
+	depth  uint64
+	counts []uint64
+	salts  [][saltSize]byte
+	total  uint64
+
+	optEpsilon   float64
+	optDelta     float64
+	optSeed      *uint64
+	conservative bool
+
+	pool *sync.Pool
+	mux  sync.RWMutex
+}
+
+type Option func(c *CMS)
+
+func HashFunc(h func() hash.Hash) Option {
+	return func(c *CMS) {
+		c.pool = &sync.Pool{New: func() any { return h() }}
+	}
+}
+
+// Accuracy sets the error bound epsilon and the failure probability delta.
+func Accuracy(epsilon, delta float64) Option {
+	return func(c *CMS) {
+		c.optEpsilon = epsilon
+		c.optDelta = delta
+	}
+}
+
+func ConservativeUpdate() Option {
+	return func(c *CMS) {
+		c.conservative = true
+	}
+}
+
+// Seed derives the hash salts from seed instead of random bytes,
+// sketches with the same seed and accuracy can be merged.
+func Seed(seed uint64) Option {
+	return func(c *CMS) {
+		c.optSeed = &seed
+	}
+}
+
+func New(opts ...Option) (*CMS, error) {
+	c := &CMS{
+		optEpsilon: 0.001,
+		optDelta:   0.01,
+		pool:       &sync.Pool{New: func() any { return xxhash.New() }},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.optEpsilon <= 0.0 || c.optEpsilon >= 1.0 {
+		return nil, fmt.Errorf("epsilon must be between 0.0 and 1.0")
+	}
+	if c.optDelta <= 0.0 || c.optDelta >= 1.0 {
+		return nil, fmt.Errorf("delta must be between 0.0 and 1.0")
+	}
+
+	c.width, c.depth = calcOptimalParams(c.optEpsilon, c.optDelta)
+	c.counts = make([]uint64, c.width*c.depth)
+	c.salts = make([][saltSize]byte, c.depth)
+
+	for i := range c.salts {
+		if c.optSeed != nil {
+			buf := binary.LittleEndian.AppendUint64(nil, *c.optSeed)
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(i))
+			binary.LittleEndian.PutUint64(c.salts[i][:], xxhash.Sum64(buf))
+		} else if _, err := rand.Read(c.salts[i][:]); err != nil {
+			return nil, fmt.Errorf("generate hash salt: %w", err)
+		}
+
+		c.salts[i] = [saltSize]byte(bytes.ReplaceAll(c.salts[i][:], []byte("\n"), []byte("~")))
+	}
+
+	return c, nil
+}
+
+func (c *CMS) Width() uint64 {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.width
+}
+
+func (c *CMS) Depth() uint64 {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.depth
+}
+
+// Total returns the sum of all added counts.
+func (c *CMS) Total() uint64 {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	return c.total
+}
+
+// Add increases the frequency of the value and returns its new estimate.
+func (c *CMS) Add(arg any, count uint64) uint64 {
+	val := anyToBytes(arg)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	cells := c.cells(val)
+
+	c.total += count
+
+	if !c.conservative {
+		result := uint64(math.MaxUint64)
+		for _, cell := range cells {
+			c.counts[cell] += count
+			result = min(result, c.counts[cell])
+		}
+		return result
+	}
+
+	result := uint64(math.MaxUint64)
+	for _, cell := range cells {
+		result = min(result, c.counts[cell])
+	}
+	result += count
+	for _, cell := range cells {
+		c.counts[cell] = max(c.counts[cell], result)
+	}
+	return result
+}
+
+func (c *CMS) Estimate(arg any) uint64 {
+	val := anyToBytes(arg)
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	cells := c.cells(val)
+
+	result := uint64(math.MaxUint64)
+	for _, cell := range cells {
+		result = min(result, c.counts[cell])
+	}
+	return result
+}
+
+// Merge adds the counters of other. The sketches must share the hash salts:
+// either both are created with the same Seed and Accuracy, or other is
+// a CopyTo/Restore of the same template.
+func (c *CMS) Merge(other *CMS) error {
+	other.mux.RLock()
+	counts := slices.Clone(other.counts)
+	salts := slices.Clone(other.salts)
+	total := other.total
+	other.mux.RUnlock()
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if len(counts) != len(c.counts) || !slices.Equal(salts, c.salts) {
+		return fmt.Errorf("sketches are not compatible")
+	}
+
+	for i, v := range counts {
+		c.counts[i] += v
+	}
+	c.total += total
+
+	return nil
+}
+
+func (c *CMS) Reset() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	clear(c.counts)
+	c.total = 0
+}
+
+func (c *CMS) CopyTo(dst *CMS) {
+	c.mux.RLock()
+	width, depth := c.width, c.depth
+	counts := slices.Clone(c.counts)
+	salts := slices.Clone(c.salts)
+	total := c.total
+	epsilon, delta := c.optEpsilon, c.optDelta
+	conservative := c.conservative
+	c.mux.RUnlock()
+
+	dst.mux.Lock()
+	defer dst.mux.Unlock()
+
+	dst.width, dst.depth = width, depth
+	dst.counts, dst.salts, dst.total = counts, salts, total
+	dst.optEpsilon, dst.optDelta = epsilon, delta
+	dst.conservative = conservative
+}
+
+func (c *CMS) Dump(w io.Writer) error {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if _, err := fmt.Fprintf(w, "OSSPkg:cms\n%d %d %d %t\n", c.width, c.depth, c.total, c.conservative); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	for _, salt := range c.salts {
+		if _, err := w.Write(salt[:]); err != nil {
+			return fmt.Errorf("write salt: %w", err)
+		}
+
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return fmt.Errorf("write salt: %w", err)
+		}
+	}
+
+	buf := make([]byte, 0, 8*c.width)
+	for row := uint64(0); row < c.depth; row++ {
+		buf = buf[:0]
+		for _, v := range c.counts[row*c.width : (row+1)*c.width] {
+			buf = binary.LittleEndian.AppendUint64(buf, v)
+		}
+		if _, err := w.Write(buf); err != nil {
+			return fmt.Errorf("write counters: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *CMS) Restore(r io.Reader) error {
+	reader := bufio.NewReader(r)
+
+	head, err := reader.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	if !bytes.Equal(head[:len(head)-1], []byte("OSSPkg:cms")) {
+		return fmt.Errorf("invalid header")
+	}
+
+	params, err := reader.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("read params: %w", err)
+	}
+
+	fields := bytes.Fields(params)
+	if len(fields) != 4 {
+		return fmt.Errorf("invalid params")
+	}
+
+	values := make([]uint64, 3)
+	for i := range values {
+		if values[i], err = strconv.ParseUint(string(fields[i]), 10, 64); err != nil {
+			return fmt.Errorf("invalid params: %w", err)
+		}
+	}
+	conservative, err := strconv.ParseBool(string(fields[3]))
+	if err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+
+	width, depth, total := values[0], values[1], values[2]
+	if width == 0 || depth == 0 || width > 1<<28 || depth > 64 {
+		return fmt.Errorf("invalid params: width %d depth %d", width, depth)
+	}
+
+	salts := make([][saltSize]byte, depth)
+	for i := range salts {
+		salt, err0 := reader.ReadBytes('\n')
+		if err0 != nil {
+			return fmt.Errorf("read salt[%d]: %w", i, err0)
+		}
+
+		salt = salt[:len(salt)-1]
+		if len(salt) != saltSize {
+			return fmt.Errorf("invalid salt[%d], want %d got %d", i, saltSize, len(salt))
+		}
+
+		salts[i] = [saltSize]byte(salt)
+	}
+
+	// the counters grow with the data actually read, not with the announced size
+	counts := make([]uint64, 0, min(width*depth, restoreChunk))
+	chunk := make([]uint64, restoreChunk)
+	for left := width * depth; left > 0; {
+		part := chunk[:min(left, restoreChunk)]
+		if err = binary.Read(reader, binary.LittleEndian, part); err != nil {
+			return fmt.Errorf("read counters: %w", err)
+		}
+		counts = append(counts, part...)
+		left -= uint64(len(part))
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.width, c.depth, c.total, c.conservative = width, depth, total, conservative
+	c.salts, c.counts = salts, counts
+
+	return nil
+}
+
+// cells returns the counter position in every row, the caller must hold the lock.
+func (c *CMS) cells(val []byte) []uint64 {
+	h, ok := c.pool.Get().(hash.Hash)
+	if !ok {
+		panic("failed get hash function from pool")
+	}
+	defer func() {
+		c.pool.Put(h)
+	}()
+
+	cells := make([]uint64, c.depth)
+	for i := range cells {
+		h.Reset()
+		h.Write(val)
+		h.Write(c.salts[i][:])
+		cells[i] = uint64(i)*c.width + binary.BigEndian.Uint64(h.Sum(nil))%c.width
+	}
+	return cells
+}
+
+func calcOptimalParams(epsilon, delta float64) (uint64, uint64) {
+	width := math.Ceil(math.E / epsilon)
+	depth := math.Ceil(math.Log(1 / delta))
+	return uint64(max(width, 1)), uint64(max(depth, 1))
+}
+
+type byter interface {
+	Bytes() []byte
+}
+
+func anyToBytes(arg any) []byte {
+	switch value := arg.(type) {
+	case []byte:
+		return value
+	case byter:
+		return value.Bytes()
+	case string:
+		return []byte(value)
+	case fmt.Stringer:
+		return []byte(value.String())
+	case int64:
+		return binary.AppendVarint(nil, value)
+	case int32:
+		return binary.AppendVarint(nil, int64(value))
+	case int16:
+		return binary.AppendVarint(nil, int64(value))
+	case int8:
+		return binary.AppendVarint(nil, int64(value))
+	case int:
+		return binary.AppendVarint(nil, int64(value))
+	case uint64:
+		return binary.AppendUvarint(nil, value)
+	case uint32:
+		return binary.AppendUvarint(nil, uint64(value))
+	case uint16:
+		return binary.AppendUvarint(nil, uint64(value))
+	case uint8:
+		return binary.AppendUvarint(nil, uint64(value))
+	case uint:
+		return binary.AppendUvarint(nil, uint64(value))
+	case json.Marshaler:
+		bb, _ := value.MarshalJSON()
+		return bb
+	case encoding.BinaryMarshaler:
+		bb, _ := value.MarshalBinary()
+		return bb
+	case encoding.TextMarshaler:
+		bb, _ := value.MarshalText()
+		return bb
+	case gob.GobEncoder:
+		bb, _ := value.GobEncode()
+		return bb
+	default:
+		return []byte(fmt.Sprintf("%+v", arg))
+	}
+}
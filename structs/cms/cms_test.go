@@ -0,0 +1,171 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_CMS_Params(t *testing.T) {
+	c, err := New(Accuracy(0.01, 0.01))
+	casecheck.NoError(t, err)
+	casecheck.Equal(t, uint64(272), c.Width())
+	casecheck.Equal(t, uint64(5), c.Depth())
+
+	_, err = New(Accuracy(0, 0.01))
+	casecheck.Error(t, err)
+	_, err = New(Accuracy(0.01, 1))
+	casecheck.Error(t, err)
+}
+
+func TestUnit_CMS_Estimate(t *testing.T) {
+	for _, conservative := range []bool{false, true} {
+		opts := []Option{Accuracy(0.001, 0.01), HashFunc(sha256.New)}
+		if conservative {
+			opts = append(opts, ConservativeUpdate())
+		}
+		c, err := New(opts...)
+		casecheck.NoError(t, err)
+
+		want := make(map[string]uint64)
+		for i := 0; i < 20_000; i++ {
+			key := fmt.Sprintf("key-%d", i%1000)
+			count := uint64(i%7 + 1)
+			want[key] += count
+			c.Add(key, count)
+		}
+
+		bound := uint64(0.001 * float64(c.Total()))
+		failed := 0
+		for key, count := range want {
+			got := c.Estimate(key)
+			casecheck.True(t, got >= count, "%s: got %d less than %d", key, got, count)
+			if got-count > bound {
+				failed++
+			}
+		}
+		casecheck.True(t, failed <= len(want)/100, "conservative=%t failed=%d", conservative, failed)
+	}
+}
+
+func TestUnit_CMS_Conservative(t *testing.T) {
+	plain, err := New(Accuracy(0.05, 0.01))
+	casecheck.NoError(t, err)
+	conservative, err := New(Accuracy(0.05, 0.01), ConservativeUpdate())
+	casecheck.NoError(t, err)
+
+	for i := 0; i < 10_000; i++ {
+		plain.Add(i, 1)
+		conservative.Add(i, 1)
+	}
+
+	var overPlain, overConservative uint64
+	for i := 0; i < 10_000; i++ {
+		overPlain += plain.Estimate(i) - 1
+		overConservative += conservative.Estimate(i) - 1
+	}
+	casecheck.True(t, overConservative < overPlain, "conservative %d plain %d", overConservative, overPlain)
+}
+
+func TestUnit_CMS_Merge(t *testing.T) {
+	a, err := New(Accuracy(0.01, 0.01))
+	casecheck.NoError(t, err)
+	b, err := New()
+	casecheck.NoError(t, err)
+	a.CopyTo(b)
+
+	for i := 0; i < 100; i++ {
+		a.Add("a", 1)
+		b.Add("b", 2)
+	}
+	casecheck.NoError(t, a.Merge(b))
+	casecheck.True(t, a.Estimate("a") >= 100)
+	casecheck.True(t, a.Estimate("b") >= 200)
+	casecheck.Equal(t, uint64(300), a.Total())
+
+	other, err := New(Accuracy(0.01, 0.01))
+	casecheck.NoError(t, err)
+	casecheck.Error(t, a.Merge(other))
+
+	// independent shards created with one seed
+	shards := make([]*CMS, 3)
+	for i := range shards {
+		shards[i], err = New(Accuracy(0.01, 0.01), Seed(42))
+		casecheck.NoError(t, err)
+		shards[i].Add(fmt.Sprintf("shard-%d", i), uint64(i+1))
+		shards[i].Add("common", 1)
+	}
+	for _, shard := range shards[1:] {
+		casecheck.NoError(t, shards[0].Merge(shard))
+	}
+	casecheck.True(t, shards[0].Estimate("common") >= 3)
+	casecheck.True(t, shards[0].Estimate("shard-2") >= 3)
+	casecheck.Equal(t, uint64(9), shards[0].Total())
+
+	other, err = New(Accuracy(0.01, 0.01), Seed(43))
+	casecheck.NoError(t, err)
+	casecheck.Error(t, shards[0].Merge(other))
+
+	a.Reset()
+	casecheck.Equal(t, uint64(0), a.Estimate("a"))
+	casecheck.Equal(t, uint64(0), a.Total())
+}
+
+func TestUnit_CMS_DumpRestore(t *testing.T) {
+	c, err := New(Accuracy(0.01, 0.001), ConservativeUpdate())
+	casecheck.NoError(t, err)
+
+	for i := 0; i < 1000; i++ {
+		c.Add(i%50, uint64(i))
+	}
+
+	buf := bytes.NewBuffer(nil)
+	casecheck.NoError(t, c.Dump(buf))
+
+	r, err := New()
+	casecheck.NoError(t, err)
+	casecheck.NoError(t, r.Restore(bytes.NewReader(buf.Bytes())))
+
+	casecheck.Equal(t, c.Width(), r.Width())
+	casecheck.Equal(t, c.Depth(), r.Depth())
+	casecheck.Equal(t, c.Total(), r.Total())
+	casecheck.True(t, r.conservative)
+	for i := 0; i < 50; i++ {
+		casecheck.Equal(t, c.Estimate(i), r.Estimate(i))
+	}
+
+	casecheck.Error(t, r.Restore(bytes.NewReader([]byte("OSSPkg:bloom\n1\n"))))
+	casecheck.Error(t, r.Restore(bytes.NewReader(buf.Bytes()[:buf.Len()-1])))
+
+	// the maximum announced size without counters must fail before allocating it
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	before := stats.TotalAlloc
+
+	casecheck.Error(t, r.Restore(bytes.NewReader([]byte("OSSPkg:cms\n268435456 64 0 false\n"+
+		strings.Repeat("saltsalt\n", 64)))))
+
+	runtime.ReadMemStats(&stats)
+	casecheck.True(t, stats.TotalAlloc-before < 1<<20, "allocated %d bytes", stats.TotalAlloc-before)
+}
+
+func Benchmark_CMS(b *testing.B) {
+	c, err := New(Accuracy(0.001, 0.01))
+	casecheck.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Add(i, 1)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// Top-K отслеживает k самых частых элементов потока. Частоты оцениваются
+// Count-Min Sketch, а кандидаты хранятся в min-куче размера k, поэтому
+// память не зависит от количества уникальных элементов.
+
+package cms
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type Item[K comparable] struct {
+	Key   K
+	Count uint64
+}
+
+type TopK[K comparable] struct {
+	k      int
+	sketch *CMS
+	items  itemHeap[K]
+	index  map[K]int
+	mux    sync.Mutex
+}
+
+func NewTopK[K comparable](k int, opts ...Option) (*TopK[K], error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("k must be greater than 0")
+	}
+
+	sketch, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &TopK[K]{
+		k:      k,
+		sketch: sketch,
+		index:  make(map[K]int, k),
+	}
+	t.items.index = t.index
+
+	return t, nil
+}
+
+// Add increases the frequency of the key and returns its new estimate.
+func (t *TopK[K]) Add(key K, count uint64) uint64 {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	est := t.sketch.Add(key, count)
+
+	if i, ok := t.index[key]; ok {
+		t.items.list[i].Count = est
+		heap.Fix(&t.items, i)
+		return est
+	}
+
+	if t.items.Len() < t.k {
+		heap.Push(&t.items, Item[K]{Key: key, Count: est})
+		return est
+	}
+
+	if est > t.items.list[0].Count {
+		delete(t.index, t.items.list[0].Key)
+		t.items.list[0] = Item[K]{Key: key, Count: est}
+		t.index[key] = 0
+		heap.Fix(&t.items, 0)
+	}
+
+	return est
+}
+
+func (t *TopK[K]) Estimate(key K) uint64 {
+	return t.sketch.Estimate(key)
+}
+
+// List returns the tracked keys ordered by frequency, most frequent first.
+func (t *TopK[K]) List() []Item[K] {
+	t.mux.Lock()
+	result := slices.Clone(t.items.list)
+	t.mux.Unlock()
+
+	slices.SortStableFunc(result, func(a, b Item[K]) int {
+		switch {
+		case a.Count > b.Count:
+			return -1
+		case a.Count < b.Count:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return result
+}
+
+func (t *TopK[K]) Reset() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.sketch.Reset()
+	t.items.list = t.items.list[:0]
+	clear(t.index)
+}
+
+type itemHeap[K comparable] struct {
+	list  []Item[K]
+	index map[K]int
+}
+
+func (h *itemHeap[K]) Len() int { return len(h.list) }
+
+func (h *itemHeap[K]) Less(i, j int) bool { return h.list[i].Count < h.list[j].Count }
+
+func (h *itemHeap[K]) Swap(i, j int) {
+	h.list[i], h.list[j] = h.list[j], h.list[i]
+	h.index[h.list[i].Key] = i
+	h.index[h.list[j].Key] = j
+}
+
+func (h *itemHeap[K]) Push(x any) {
+	item, ok := x.(Item[K])
+	if !ok {
+		panic("invalid heap item")
+	}
+	h.index[item.Key] = len(h.list)
+	h.list = append(h.list, item)
+}
+
+func (h *itemHeap[K]) Pop() any {
+	item := h.list[len(h.list)-1]
+	h.list = h.list[:len(h.list)-1]
+	delete(h.index, item.Key)
+	return item
+}
@@ -0,0 +1,42 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package cms
+
+import (
+	"fmt"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_TopK(t *testing.T) {
+	top, err := NewTopK[string](3, Accuracy(0.001, 0.01))
+	casecheck.NoError(t, err)
+
+	heavy := map[string]int{"alpha": 500, "beta": 300, "gamma": 200}
+	for round := 0; round < 500; round++ {
+		for key, n := range heavy {
+			if round < n {
+				top.Add(key, 1)
+			}
+		}
+		top.Add(fmt.Sprintf("noise-%d", round), 1)
+		top.Add(fmt.Sprintf("noise-%d", round%50), 1)
+	}
+
+	list := top.List()
+	casecheck.Equal(t, 3, len(list))
+	casecheck.Equal(t, "alpha", list[0].Key)
+	casecheck.Equal(t, "beta", list[1].Key)
+	casecheck.Equal(t, "gamma", list[2].Key)
+	casecheck.True(t, list[0].Count >= 500)
+
+	top.Reset()
+	casecheck.Equal(t, 0, len(top.List()))
+
+	_, err = NewTopK[int](0)
+	casecheck.Error(t, err)
+}
@@ -0,0 +1,314 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://research.google/pubs/hyperloglog-in-practice-algorithmic-engineering-of-a-state-of-the-art-cardinality-estimation-algorithm/
+// see: https://arxiv.org/abs/1702.01284
+
+// HyperLogLog - вероятностная структура для оценки количества уникальных элементов.
+// Пока элементов мало, используется разреженное представление с точностью 2^25
+// и линейным подсчетом, затем структура переходит к плотному массиву регистров.
+// Для плотного массива используется улучшенная оценка Ertl, не требующая
+// эмпирических таблиц коррекции смещения.
+
+package hll
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	MinPrecision = 4
+	MaxPrecision = 18
+
+	sparsePrecision = 25
+	rhoBits         = 6
+	rhoMask         = 1<<rhoBits - 1
+)
+
+type HLL struct {
+	p uint8
+
+	// sparse holds sorted values of (index << rhoBits | rho) at sparsePrecision
+	sparse []uint32
+	dense  []uint8
+
+	mux sync.RWMutex
+}
+
+type Option func(h *HLL)
+
+func Precision(p uint8) Option {
+	return func(h *HLL) {
+		h.p = p
+	}
+}
+
+func New(opts ...Option) (*HLL, error) {
+	h := &HLL{p: 14}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.p < MinPrecision || h.p > MaxPrecision {
+		return nil, fmt.Errorf("precision must be between %d and %d", MinPrecision, MaxPrecision)
+	}
+
+	return h, nil
+}
+
+func (h *HLL) Precision() uint8 {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	return h.p
+}
+
+func (h *HLL) Add(arg any) {
+	h.AddHash(xxhash.Sum64(anyToBytes(arg)))
+}
+
+// AddHash adds an already hashed value, the hash must be uniformly distributed.
+func (h *HLL) AddHash(x uint64) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if h.dense != nil {
+		index, rho := split(x, h.p)
+		h.dense[index] = max(h.dense[index], rho)
+		return
+	}
+
+	index, rho := split(x, sparsePrecision)
+	h.insertSparse(index<<rhoBits | uint32(rho))
+
+	if len(h.sparse) > h.sparseLimit() {
+		h.toDense()
+	}
+}
+
+func (h *HLL) Count() uint64 {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	if h.dense == nil {
+		m := float64(uint64(1) << sparsePrecision)
+		return uint64(math.Round(m * math.Log(m/(m-float64(len(h.sparse))))))
+	}
+
+	return uint64(math.Round(estimate(h.dense, h.p)))
+}
+
+func (h *HLL) Merge(other *HLL) error {
+	if h == other {
+		return nil
+	}
+
+	sparse, dense, p := other.snapshot()
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if p != h.p {
+		return fmt.Errorf("precision mismatch: %d != %d", h.p, p)
+	}
+
+	if h.dense == nil && dense == nil {
+		for _, v := range sparse {
+			h.insertSparse(v)
+		}
+		if len(h.sparse) > h.sparseLimit() {
+			h.toDense()
+		}
+		return nil
+	}
+
+	if h.dense == nil {
+		h.toDense()
+	}
+	if dense == nil {
+		dense = sparseToDense(sparse, p)
+	}
+	for i, rho := range dense {
+		h.dense[i] = max(h.dense[i], rho)
+	}
+
+	return nil
+}
+
+func (h *HLL) Reset() {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.sparse, h.dense = nil, nil
+}
+
+func (h *HLL) snapshot() ([]uint32, []uint8, uint8) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	return slices.Clone(h.sparse), slices.Clone(h.dense), h.p
+}
+
+func (h *HLL) sparseLimit() int {
+	// four bytes per sparse value against one byte per dense register
+	return (1 << h.p) / 4
+}
+
+func (h *HLL) insertSparse(v uint32) {
+	i, ok := slices.BinarySearchFunc(h.sparse, v>>rhoBits, func(e, index uint32) int {
+		return int(e>>rhoBits) - int(index)
+	})
+	switch {
+	case !ok:
+		h.sparse = slices.Insert(h.sparse, i, v)
+	case v&rhoMask > h.sparse[i]&rhoMask:
+		h.sparse[i] = v
+	}
+}
+
+func (h *HLL) toDense() {
+	h.dense = sparseToDense(h.sparse, h.p)
+	h.sparse = nil
+}
+
+func sparseToDense(sparse []uint32, p uint8) []uint8 {
+	dense := make([]uint8, 1<<p)
+	shift := sparsePrecision - p
+
+	for _, v := range sparse {
+		index, rho := v>>rhoBits, uint8(v&rhoMask)
+
+		if low := index & (1<<shift - 1); low != 0 {
+			rho = uint8(bits.LeadingZeros32(low<<(32-shift))) + 1
+		} else {
+			rho += shift
+		}
+
+		index >>= shift
+		dense[index] = max(dense[index], rho)
+	}
+
+	return dense
+}
+
+// split returns the register index from the first p bits of the hash
+// and the position of the leftmost one bit in the remaining bits.
+func split(x uint64, p uint8) (uint32, uint8) {
+	index := uint32(x >> (64 - p))
+	rho := min(bits.LeadingZeros64(x<<p), 64-int(p)) + 1
+	return index, uint8(rho)
+}
+
+func estimate(dense []uint8, p uint8) float64 {
+	q := 64 - int(p)
+	m := float64(len(dense))
+
+	hist := make([]int, q+2)
+	for _, rho := range dense {
+		hist[rho]++
+	}
+
+	z := m * tau(1-float64(hist[q+1])/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + float64(hist[k]))
+	}
+	z += m * sigma(float64(hist[0])/m)
+
+	return m * m / (2 * math.Ln2 * z)
+}
+
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		prev := z
+		z += x * y
+		y += y
+		if z == prev {
+			return z
+		}
+	}
+}
+
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		prev := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if z == prev {
+			return z / 3
+		}
+	}
+}
+
+type byter interface {
+	Bytes() []byte
+}
+
+func anyToBytes(arg any) []byte {
+	switch value := arg.(type) {
+	case []byte:
+		return value
+	case byter:
+		return value.Bytes()
+	case string:
+		return []byte(value)
+	case fmt.Stringer:
+		return []byte(value.String())
+	case int64:
+		return binary.AppendVarint(nil, value)
+	case int32:
+		return binary.AppendVarint(nil, int64(value))
+	case int16:
+		return binary.AppendVarint(nil, int64(value))
+	case int8:
+		return binary.AppendVarint(nil, int64(value))
+	case int:
+		return binary.AppendVarint(nil, int64(value))
+	case uint64:
+		return binary.AppendUvarint(nil, value)
+	case uint32:
+		return binary.AppendUvarint(nil, uint64(value))
+	case uint16:
+		return binary.AppendUvarint(nil, uint64(value))
+	case uint8:
+		return binary.AppendUvarint(nil, uint64(value))
+	case uint:
+		return binary.AppendUvarint(nil, uint64(value))
+	case json.Marshaler:
+		bb, _ := value.MarshalJSON()
+		return bb
+	case encoding.BinaryMarshaler:
+		bb, _ := value.MarshalBinary()
+		return bb
+	case encoding.TextMarshaler:
+		bb, _ := value.MarshalText()
+		return bb
+	case gob.GobEncoder:
+		bb, _ := value.GobEncode()
+		return bb
+	default:
+		return []byte(fmt.Sprintf("%+v", arg))
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package hll
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func relativeError(got uint64, want int) float64 {
+	return math.Abs(float64(got)-float64(want)) / float64(want)
+}
+
+func TestUnit_HLL_Accuracy(t *testing.T) {
+	for _, p := range []uint8{10, 14} {
+		h, err := New(Precision(p))
+		casecheck.NoError(t, err)
+
+		// standard error of HLL is 1.04/sqrt(m)
+		maxErr := 4 * 1.04 / math.Sqrt(float64(uint64(1)<<p))
+
+		added := 0
+		for _, n := range []int{10, 100, 1_000, 10_000, 100_000, 1_000_000} {
+			for ; added < n; added++ {
+				h.Add(added)
+			}
+			got := h.Count()
+			casecheck.True(t, relativeError(got, n) < maxErr, "p=%d n=%d got=%d", p, n, got)
+		}
+	}
+}
+
+func TestUnit_HLL_Sparse(t *testing.T) {
+	h, err := New(Precision(14))
+	casecheck.NoError(t, err)
+
+	for i := 0; i < 1_000; i++ {
+		h.Add(i)
+		h.Add(i)
+	}
+	casecheck.True(t, h.dense == nil, "want sparse representation")
+	casecheck.True(t, relativeError(h.Count(), 1_000) < 0.01)
+
+	for i := 0; i < 10_000; i++ {
+		h.Add(i)
+	}
+	casecheck.True(t, h.dense != nil, "want dense representation")
+}
+
+func TestUnit_HLL_Merge(t *testing.T) {
+	a, err := New(Precision(12))
+	casecheck.NoError(t, err)
+	b, err := New(Precision(12))
+	casecheck.NoError(t, err)
+	c, err := New(Precision(12))
+	casecheck.NoError(t, err)
+
+	for i := 0; i < 50_000; i++ {
+		a.Add(i)
+	}
+	for i := 25_000; i < 75_000; i++ {
+		b.Add(i)
+	}
+	for i := 0; i < 100; i++ {
+		c.Add(i)
+	}
+
+	casecheck.NoError(t, c.Merge(a))
+	casecheck.NoError(t, c.Merge(b))
+	casecheck.True(t, relativeError(c.Count(), 75_000) < 0.05, "got %d", c.Count())
+
+	sa, err := New(Precision(12))
+	casecheck.NoError(t, err)
+	sb, err := New(Precision(12))
+	casecheck.NoError(t, err)
+	for i := 0; i < 300; i++ {
+		sa.Add(i)
+		sb.Add(i + 200)
+	}
+	casecheck.NoError(t, sa.Merge(sb))
+	casecheck.True(t, relativeError(sa.Count(), 500) < 0.02, "got %d", sa.Count())
+
+	other, err := New(Precision(10))
+	casecheck.NoError(t, err)
+	casecheck.Error(t, a.Merge(other))
+}
+
+func TestUnit_HLL_ConcurrentUnmarshal(t *testing.T) {
+	h, err := New(Precision(12))
+	casecheck.NoError(t, err)
+	other, err := New(Precision(10))
+	casecheck.NoError(t, err)
+	data, err := other.MarshalBinary()
+	casecheck.NoError(t, err)
+
+	// UnmarshalBinary changes the precision while Merge and Precision read it
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = h.UnmarshalBinary(data) //nolint:errcheck
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = h.Merge(other) //nolint:errcheck
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = h.Precision()
+		}
+	}()
+	wg.Wait()
+
+	casecheck.Equal(t, uint8(10), h.Precision())
+}
+
+func TestUnit_HLL_Marshal(t *testing.T) {
+	for _, n := range []int{0, 500, 100_000} {
+		h, err := New(Precision(12))
+		casecheck.NoError(t, err)
+		for i := 0; i < n; i++ {
+			h.Add(i)
+		}
+
+		data, err := h.MarshalBinary()
+		casecheck.NoError(t, err)
+
+		restored, err := New(Precision(4))
+		casecheck.NoError(t, err)
+		casecheck.NoError(t, restored.UnmarshalBinary(data))
+		casecheck.Equal(t, uint8(12), restored.Precision())
+		casecheck.Equal(t, h.Count(), restored.Count())
+	}
+
+	h, err := New()
+	casecheck.NoError(t, err)
+	for _, data := range [][]byte{nil, {9, 12, 0, 0}, {1, 2, 0, 0}, {1, 12, 7}, {1, 4, 1, 0},
+		{1, 14, 0, 1, 63},    // rho above the sparse limit
+		{1, 14, 0, 1, 64},    // zero rho
+		{1, 14, 0, 2, 65, 1}, // repeated register index
+	} {
+		casecheck.True(t, errors.Is(h.UnmarshalBinary(data), ErrInvalidFormat), "data %v", data)
+	}
+}
+
+func TestUnit_HLL_Options(t *testing.T) {
+	_, err := New(Precision(3))
+	casecheck.Error(t, err)
+	_, err = New(Precision(19))
+	casecheck.Error(t, err)
+}
+
+func Benchmark_HLL_Add(b *testing.B) {
+	h, err := New()
+	if err != nil {
+		b.FailNow()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		h.Add(i)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package hll
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	formatVersion = 1
+
+	formatSparse = 0
+	formatDense  = 1
+)
+
+var ErrInvalidFormat = errors.New("invalid hll format")
+
+// MarshalBinary encodes the sketch as: version, precision, format and
+// either delta-encoded sparse values or the dense registers.
+func (h *HLL) MarshalBinary() ([]byte, error) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	if h.dense != nil {
+		out := make([]byte, 0, 3+len(h.dense))
+		out = append(out, formatVersion, h.p, formatDense)
+		return append(out, h.dense...), nil
+	}
+
+	out := make([]byte, 0, 3+binary.MaxVarintLen32*(len(h.sparse)+1))
+	out = append(out, formatVersion, h.p, formatSparse)
+	out = binary.AppendUvarint(out, uint64(len(h.sparse)))
+
+	var prev uint32
+	for _, v := range h.sparse {
+		out = binary.AppendUvarint(out, uint64(v-prev))
+		prev = v
+	}
+
+	return out, nil
+}
+
+func (h *HLL) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 {
+		return fmt.Errorf("%w: too short", ErrInvalidFormat)
+	}
+	if data[0] != formatVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidFormat, data[0])
+	}
+
+	p := data[1]
+	if p < MinPrecision || p > MaxPrecision {
+		return fmt.Errorf("%w: invalid precision %d", ErrInvalidFormat, p)
+	}
+
+	var (
+		sparse []uint32
+		dense  []uint8
+	)
+
+	switch data[2] {
+	case formatDense:
+		if len(data)-3 != 1<<p {
+			return fmt.Errorf("%w: invalid registers count", ErrInvalidFormat)
+		}
+		dense = make([]uint8, 1<<p)
+		copy(dense, data[3:])
+		for _, rho := range dense {
+			if int(rho) > 65-int(p) {
+				return fmt.Errorf("%w: invalid register value", ErrInvalidFormat)
+			}
+		}
+
+	case formatSparse:
+		data = data[3:]
+		count, n := binary.Uvarint(data)
+		if n <= 0 || count > 1<<sparsePrecision {
+			return fmt.Errorf("%w: invalid sparse count", ErrInvalidFormat)
+		}
+		data = data[n:]
+
+		sparse = make([]uint32, 0, min(count, uint64(len(data))))
+		var prev uint64
+		for i := uint64(0); i < count; i++ {
+			delta, n := binary.Uvarint(data)
+			if n <= 0 || (i > 0 && delta == 0) || prev+delta > 1<<(sparsePrecision+rhoBits)-1 {
+				return fmt.Errorf("%w: invalid sparse value", ErrInvalidFormat)
+			}
+			data = data[n:]
+
+			// each register appears once and holds a rank possible at sparsePrecision
+			v := uint32(prev + delta)
+			if rho := v & rhoMask; rho == 0 || rho > 64-sparsePrecision+1 ||
+				(i > 0 && v>>rhoBits == uint32(prev)>>rhoBits) {
+				return fmt.Errorf("%w: invalid sparse value", ErrInvalidFormat)
+			}
+			prev += delta
+			sparse = append(sparse, v)
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown format %d", ErrInvalidFormat, data[2])
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.p, h.sparse, h.dense = p, sparse, dense
+
+	return nil
+}
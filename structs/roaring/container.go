@@ -0,0 +1,350 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package roaring
+
+import (
+	"math/bits"
+	"slices"
+)
+
+const (
+	arrayMaxSize = 4096
+	bitmapWords  = (1 << 16) / 64
+)
+
+type container interface {
+	add(v uint16) container
+	remove(v uint16) container
+	contains(v uint16) bool
+	cardinality() int
+	clone() container
+	all(yield func(uint16) bool) bool
+}
+
+// arrayContainer хранит отсортированный список значений, используется для разреженных блоков.
+type arrayContainer struct {
+	values []uint16
+}
+
+func (c *arrayContainer) add(v uint16) container {
+	i, ok := slices.BinarySearch(c.values, v)
+	if ok {
+		return c
+	}
+	if len(c.values) >= arrayMaxSize {
+		return toBitmap(c).add(v)
+	}
+	c.values = slices.Insert(c.values, i, v)
+	return c
+}
+
+func (c *arrayContainer) remove(v uint16) container {
+	if i, ok := slices.BinarySearch(c.values, v); ok {
+		c.values = slices.Delete(c.values, i, i+1)
+	}
+	return c
+}
+
+func (c *arrayContainer) contains(v uint16) bool {
+	_, ok := slices.BinarySearch(c.values, v)
+	return ok
+}
+
+func (c *arrayContainer) cardinality() int {
+	return len(c.values)
+}
+
+func (c *arrayContainer) clone() container {
+	return &arrayContainer{values: slices.Clone(c.values)}
+}
+
+func (c *arrayContainer) all(yield func(uint16) bool) bool {
+	for _, v := range c.values {
+		if !yield(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// filter keeps the values whose presence in other equals keep.
+func (c *arrayContainer) filter(other container, keep bool) container {
+	result := &arrayContainer{values: make([]uint16, 0, len(c.values))}
+	for _, v := range c.values {
+		if other.contains(v) == keep {
+			result.values = append(result.values, v)
+		}
+	}
+	return result
+}
+
+// bitmapContainer хранит плотный блок из 2^16 бит.
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (c *bitmapContainer) add(v uint16) container {
+	mask := uint64(1) << (v % 64)
+	if c.words[v/64]&mask == 0 {
+		c.words[v/64] |= mask
+		c.card++
+	}
+	return c
+}
+
+func (c *bitmapContainer) remove(v uint16) container {
+	mask := uint64(1) << (v % 64)
+	if c.words[v/64]&mask != 0 {
+		c.words[v/64] &^= mask
+		c.card--
+	}
+	if c.card <= arrayMaxSize {
+		return toArray(c)
+	}
+	return c
+}
+
+func (c *bitmapContainer) contains(v uint16) bool {
+	return c.words[v/64]&(1<<(v%64)) != 0
+}
+
+func (c *bitmapContainer) cardinality() int {
+	return c.card
+}
+
+func (c *bitmapContainer) clone() container {
+	dst := *c
+	return &dst
+}
+
+func (c *bitmapContainer) all(yield func(uint16) bool) bool {
+	for i, word := range c.words {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			if !yield(uint16(i*64 + bit)) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+func (c *bitmapContainer) setRange(from, to int) {
+	for v := from; v <= to; v++ {
+		c.words[v/64] |= 1 << (v % 64)
+	}
+}
+
+// runContainer хранит последовательности подряд идущих значений.
+// Изменение контейнера переводит его в array или bitmap представление.
+type runContainer struct {
+	runs []interval
+}
+
+type interval struct {
+	start  uint16
+	length uint16 // number of values in the run minus one
+}
+
+func (r interval) last() int {
+	return int(r.start) + int(r.length)
+}
+
+func (c *runContainer) add(v uint16) container {
+	if c.contains(v) {
+		return c
+	}
+	return toEfficient(c).add(v)
+}
+
+func (c *runContainer) remove(v uint16) container {
+	if !c.contains(v) {
+		return c
+	}
+	return toEfficient(c).remove(v)
+}
+
+func (c *runContainer) contains(v uint16) bool {
+	i, _ := slices.BinarySearchFunc(c.runs, v, func(r interval, v uint16) int {
+		switch {
+		case r.last() < int(v):
+			return -1
+		case int(r.start) > int(v):
+			return 1
+		default:
+			return 0
+		}
+	})
+	return i < len(c.runs) && c.runs[i].start <= v && int(v) <= c.runs[i].last()
+}
+
+func (c *runContainer) cardinality() int {
+	card := 0
+	for _, r := range c.runs {
+		card += int(r.length) + 1
+	}
+	return card
+}
+
+func (c *runContainer) clone() container {
+	return &runContainer{runs: slices.Clone(c.runs)}
+}
+
+func (c *runContainer) all(yield func(uint16) bool) bool {
+	for _, r := range c.runs {
+		for v := int(r.start); v <= r.last(); v++ {
+			if !yield(uint16(v)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func toBitmap(c container) *bitmapContainer {
+	switch v := c.(type) {
+	case *bitmapContainer:
+		return v
+	case *runContainer:
+		result := &bitmapContainer{card: v.cardinality()}
+		for _, r := range v.runs {
+			result.setRange(int(r.start), r.last())
+		}
+		return result
+	default:
+		result := &bitmapContainer{}
+		c.all(func(x uint16) bool {
+			result.add(x)
+			return true
+		})
+		return result
+	}
+}
+
+func toArray(c container) *arrayContainer {
+	if v, ok := c.(*arrayContainer); ok {
+		return v
+	}
+	result := &arrayContainer{values: make([]uint16, 0, c.cardinality())}
+	c.all(func(x uint16) bool {
+		result.values = append(result.values, x)
+		return true
+	})
+	return result
+}
+
+func toRun(c container) *runContainer {
+	if v, ok := c.(*runContainer); ok {
+		return v
+	}
+	result := &runContainer{}
+	c.all(func(x uint16) bool {
+		if n := len(result.runs); n > 0 && result.runs[n-1].last()+1 == int(x) {
+			result.runs[n-1].length++
+		} else {
+			result.runs = append(result.runs, interval{start: x})
+		}
+		return true
+	})
+	return result
+}
+
+// toEfficient converts the container to array or bitmap form by its cardinality.
+func toEfficient(c container) container {
+	if c.cardinality() <= arrayMaxSize {
+		return toArray(c)
+	}
+	return toBitmap(c)
+}
+
+func countRuns(c container) int {
+	if v, ok := c.(*runContainer); ok {
+		return len(v.runs)
+	}
+	runs, prev := 0, -2
+	c.all(func(x uint16) bool {
+		if int(x) != prev+1 {
+			runs++
+		}
+		prev = int(x)
+		return true
+	})
+	return runs
+}
+
+func andContainers(a, b container) container {
+	if x, ok := a.(*arrayContainer); ok {
+		return x.filter(b, true)
+	}
+	if y, ok := b.(*arrayContainer); ok {
+		return y.filter(a, true)
+	}
+	return mergeBitmaps(toBitmap(a), toBitmap(b), func(x, y uint64) uint64 { return x & y })
+}
+
+func andNotContainers(a, b container) container {
+	if x, ok := a.(*arrayContainer); ok {
+		return x.filter(b, false)
+	}
+	return mergeBitmaps(toBitmap(a), toBitmap(b), func(x, y uint64) uint64 { return x &^ y })
+}
+
+func orContainers(a, b container) container {
+	x, okx := a.(*arrayContainer)
+	y, oky := b.(*arrayContainer)
+	if okx && oky && len(x.values)+len(y.values) <= arrayMaxSize {
+		return mergeArrays(x, y, true)
+	}
+	return mergeBitmaps(toBitmap(a), toBitmap(b), func(x, y uint64) uint64 { return x | y })
+}
+
+func xorContainers(a, b container) container {
+	x, okx := a.(*arrayContainer)
+	y, oky := b.(*arrayContainer)
+	if okx && oky && len(x.values)+len(y.values) <= arrayMaxSize {
+		return mergeArrays(x, y, false)
+	}
+	return mergeBitmaps(toBitmap(a), toBitmap(b), func(x, y uint64) uint64 { return x ^ y })
+}
+
+// mergeArrays returns the union of two arrays, or the symmetric difference when union is false.
+func mergeArrays(a, b *arrayContainer, union bool) container {
+	result := &arrayContainer{values: make([]uint16, 0, len(a.values)+len(b.values))}
+	i, j := 0, 0
+	for i < len(a.values) && j < len(b.values) {
+		switch {
+		case a.values[i] < b.values[j]:
+			result.values = append(result.values, a.values[i])
+			i++
+		case a.values[i] > b.values[j]:
+			result.values = append(result.values, b.values[j])
+			j++
+		default:
+			if union {
+				result.values = append(result.values, a.values[i])
+			}
+			i++
+			j++
+		}
+	}
+	result.values = append(result.values, a.values[i:]...)
+	result.values = append(result.values, b.values[j:]...)
+	return result
+}
+
+func mergeBitmaps(a, b *bitmapContainer, op func(x, y uint64) uint64) container {
+	result := &bitmapContainer{}
+	for i := range result.words {
+		result.words[i] = op(a.words[i], b.words[i])
+		result.card += bits.OnesCount64(result.words[i])
+	}
+	if result.card <= arrayMaxSize {
+		return toArray(result)
+	}
+	return result
+}
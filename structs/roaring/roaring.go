@@ -0,0 +1,206 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://roaringbitmap.org/
+// see: https://arxiv.org/abs/1603.06549
+
+// Roaring bitmap - сжатый битовый массив для множества 32-битных чисел.
+// Пространство делится на блоки по 2^16 значений по старшим 16 битам,
+// каждый блок хранится в наиболее выгодном контейнере: отсортированном массиве
+// для разреженных данных, плотном битовом массиве или списке последовательностей.
+// Структура не потокобезопасна.
+
+package roaring
+
+import (
+	"iter"
+	"slices"
+)
+
+type Bitmap struct {
+	keys       []uint16
+	containers []container
+}
+
+func New(values ...uint32) *Bitmap {
+	b := &Bitmap{}
+	for _, v := range values {
+		b.Add(v)
+	}
+	return b
+}
+
+func split(x uint32) (uint16, uint16) {
+	return uint16(x >> 16), uint16(x)
+}
+
+func (b *Bitmap) Add(x uint32) {
+	hi, lo := split(x)
+
+	i, ok := slices.BinarySearch(b.keys, hi)
+	if !ok {
+		b.keys = slices.Insert(b.keys, i, hi)
+		b.containers = slices.Insert(b.containers, i, container(&arrayContainer{}))
+	}
+
+	b.containers[i] = b.containers[i].add(lo)
+}
+
+func (b *Bitmap) Remove(x uint32) {
+	hi, lo := split(x)
+
+	i, ok := slices.BinarySearch(b.keys, hi)
+	if !ok {
+		return
+	}
+
+	b.containers[i] = b.containers[i].remove(lo)
+	if b.containers[i].cardinality() == 0 {
+		b.keys = slices.Delete(b.keys, i, i+1)
+		b.containers = slices.Delete(b.containers, i, i+1)
+	}
+}
+
+func (b *Bitmap) Contains(x uint32) bool {
+	hi, lo := split(x)
+
+	i, ok := slices.BinarySearch(b.keys, hi)
+	return ok && b.containers[i].contains(lo)
+}
+
+func (b *Bitmap) Cardinality() uint64 {
+	var card uint64
+	for _, c := range b.containers {
+		card += uint64(c.cardinality())
+	}
+	return card
+}
+
+func (b *Bitmap) IsEmpty() bool {
+	return len(b.keys) == 0
+}
+
+func (b *Bitmap) Clear() {
+	b.keys, b.containers = nil, nil
+}
+
+func (b *Bitmap) Clone() *Bitmap {
+	dst := &Bitmap{
+		keys:       slices.Clone(b.keys),
+		containers: make([]container, len(b.containers)),
+	}
+	for i, c := range b.containers {
+		dst.containers[i] = c.clone()
+	}
+	return dst
+}
+
+func (b *Bitmap) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range b.containers {
+			hi := uint32(b.keys[i]) << 16
+			if !c.all(func(lo uint16) bool { return yield(hi | uint32(lo)) }) {
+				return
+			}
+		}
+	}
+}
+
+func (b *Bitmap) ToArray() []uint32 {
+	return slices.AppendSeq(make([]uint32, 0, b.Cardinality()), b.All())
+}
+
+// RunOptimize converts containers to runs where that takes less space.
+func (b *Bitmap) RunOptimize() {
+	for i, c := range b.containers {
+		card, runs := c.cardinality(), countRuns(c)
+
+		size := bitmapWords * 8
+		if card <= arrayMaxSize {
+			size = card * 2
+		}
+
+		switch {
+		case 2+4*runs < size:
+			b.containers[i] = toRun(c)
+		default:
+			b.containers[i] = toEfficient(c)
+		}
+	}
+}
+
+func And(a, b *Bitmap) *Bitmap {
+	dst := a.Clone()
+	dst.And(b)
+	return dst
+}
+
+func Or(a, b *Bitmap) *Bitmap {
+	dst := a.Clone()
+	dst.Or(b)
+	return dst
+}
+
+func Xor(a, b *Bitmap) *Bitmap {
+	dst := a.Clone()
+	dst.Xor(b)
+	return dst
+}
+
+func AndNot(a, b *Bitmap) *Bitmap {
+	dst := a.Clone()
+	dst.AndNot(b)
+	return dst
+}
+
+func (b *Bitmap) And(other *Bitmap) {
+	b.merge(other, andContainers, false, false)
+}
+
+func (b *Bitmap) Or(other *Bitmap) {
+	b.merge(other, orContainers, true, true)
+}
+
+func (b *Bitmap) Xor(other *Bitmap) {
+	b.merge(other, xorContainers, true, true)
+}
+
+func (b *Bitmap) AndNot(other *Bitmap) {
+	b.merge(other, andNotContainers, true, false)
+}
+
+// merge walks both key lists, applies op to the shared keys and keeps
+// the keys present only in b or only in other according to the flags.
+func (b *Bitmap) merge(other *Bitmap, op func(a, b container) container, keepOwn, keepOther bool) {
+	keys := make([]uint16, 0, len(b.keys)+len(other.keys))
+	containers := make([]container, 0, len(b.keys)+len(other.keys))
+
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(other.keys) {
+		switch {
+		case j >= len(other.keys) || (i < len(b.keys) && b.keys[i] < other.keys[j]):
+			if keepOwn {
+				keys = append(keys, b.keys[i])
+				containers = append(containers, b.containers[i])
+			}
+			i++
+		case i >= len(b.keys) || b.keys[i] > other.keys[j]:
+			if keepOther {
+				keys = append(keys, other.keys[j])
+				containers = append(containers, other.containers[j].clone())
+			}
+			j++
+		default:
+			if c := op(b.containers[i], other.containers[j]); c.cardinality() > 0 {
+				keys = append(keys, b.keys[i])
+				containers = append(containers, c)
+			}
+			i++
+			j++
+		}
+	}
+
+	b.keys, b.containers = keys, containers
+}
@@ -0,0 +1,157 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package roaring
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func randomSet(rnd *rand.Rand) map[uint32]struct{} {
+	set := make(map[uint32]struct{})
+	// sparse values
+	for i := 0; i < 1000; i++ {
+		set[rnd.Uint32()%(1<<20)] = struct{}{}
+	}
+	// dense block
+	for i := 0; i < 10_000; i++ {
+		set[3<<16|uint32(rnd.Intn(1<<16))] = struct{}{}
+	}
+	// long run
+	for i := uint32(0); i < 20_000; i++ {
+		set[7<<16+i] = struct{}{}
+	}
+	return set
+}
+
+func sortedKeys(set map[uint32]struct{}) []uint32 {
+	result := make([]uint32, 0, len(set))
+	for k := range set {
+		result = append(result, k)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestUnit_Roaring_Basic(t *testing.T) {
+	b := New(1, 2, 3, 1<<16, 1<<31, 0xFFFFFFFF)
+
+	casecheck.Equal(t, uint64(6), b.Cardinality())
+	casecheck.True(t, b.Contains(1<<31))
+	casecheck.False(t, b.Contains(4))
+	casecheck.Equal(t, []uint32{1, 2, 3, 1 << 16, 1 << 31, 0xFFFFFFFF}, b.ToArray())
+
+	b.Remove(1 << 16)
+	b.Remove(100)
+	casecheck.False(t, b.Contains(1<<16))
+	casecheck.Equal(t, uint64(5), b.Cardinality())
+
+	b.Clear()
+	casecheck.True(t, b.IsEmpty())
+}
+
+func TestUnit_Roaring_Containers(t *testing.T) {
+	b := New()
+	for i := uint32(0); i < 10_000; i++ {
+		b.Add(i * 2)
+	}
+	_, ok := b.containers[0].(*bitmapContainer)
+	casecheck.True(t, ok, "want bitmap container")
+
+	for i := uint32(0); i < 6_000; i++ {
+		b.Remove(i * 2)
+	}
+	_, ok = b.containers[0].(*arrayContainer)
+	casecheck.True(t, ok, "want array container")
+	casecheck.Equal(t, uint64(4_000), b.Cardinality())
+
+	r := New()
+	for i := uint32(100); i < 30_000; i++ {
+		r.Add(i)
+	}
+	r.RunOptimize()
+	_, ok = r.containers[0].(*runContainer)
+	casecheck.True(t, ok, "want run container")
+	casecheck.True(t, r.Contains(100))
+	casecheck.True(t, r.Contains(29_999))
+	casecheck.False(t, r.Contains(99))
+	casecheck.False(t, r.Contains(30_000))
+	casecheck.Equal(t, uint64(29_900), r.Cardinality())
+
+	r.Add(5)
+	r.Remove(200)
+	casecheck.True(t, r.Contains(5))
+	casecheck.False(t, r.Contains(200))
+	casecheck.Equal(t, uint64(29_900), r.Cardinality())
+}
+
+func TestUnit_Roaring_Algebra(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	setA, setB := randomSet(rnd), randomSet(rnd)
+
+	a, b := New(sortedKeys(setA)...), New(sortedKeys(setB)...)
+	b.RunOptimize()
+
+	tests := []struct {
+		name string
+		op   func(a, b *Bitmap) *Bitmap
+		keep func(inA, inB bool) bool
+	}{
+		{name: "And", op: And, keep: func(inA, inB bool) bool { return inA && inB }},
+		{name: "Or", op: Or, keep: func(inA, inB bool) bool { return inA || inB }},
+		{name: "Xor", op: Xor, keep: func(inA, inB bool) bool { return inA != inB }},
+		{name: "AndNot", op: AndNot, keep: func(inA, inB bool) bool { return inA && !inB }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make(map[uint32]struct{})
+			for k := range setA {
+				if _, inB := setB[k]; tt.keep(true, inB) {
+					want[k] = struct{}{}
+				}
+			}
+			for k := range setB {
+				if _, inA := setA[k]; tt.keep(inA, true) {
+					want[k] = struct{}{}
+				}
+			}
+
+			got := tt.op(a, b)
+			casecheck.Equal(t, uint64(len(want)), got.Cardinality())
+			casecheck.Equal(t, sortedKeys(want), got.ToArray())
+		})
+	}
+
+	casecheck.Equal(t, sortedKeys(setA), a.ToArray())
+	casecheck.Equal(t, sortedKeys(setB), b.ToArray())
+}
+
+func TestUnit_Roaring_All(t *testing.T) {
+	b := New(1, 5, 1<<20)
+
+	var got []uint32
+	for v := range b.All() {
+		got = append(got, v)
+		if len(got) == 2 {
+			break
+		}
+	}
+	casecheck.Equal(t, []uint32{1, 5}, got)
+}
+
+func Benchmark_Roaring_Add(b *testing.B) {
+	rb := New()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		rb.Add(uint32(i) * 7)
+	}
+}
@@ -0,0 +1,256 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+// see: https://github.com/RoaringBitmap/RoaringFormatSpec
+
+package roaring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+const (
+	serialCookie               = 12347
+	serialCookieNoRunContainer = 12346
+	noOffsetThreshold          = 4
+)
+
+var ErrInvalidFormat = errors.New("invalid roaring format")
+
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if _, err := b.WriteTo(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	_, err := b.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+func (b *Bitmap) WriteTo(w io.Writer) (int64, error) {
+	size := len(b.keys)
+
+	hasRun := false
+	for _, c := range b.containers {
+		if _, ok := c.(*runContainer); ok {
+			hasRun = true
+			break
+		}
+	}
+
+	header := make([]byte, 0, 8+(size+7)/8+8*size)
+	if hasRun {
+		header = binary.LittleEndian.AppendUint32(header, serialCookie|uint32(size-1)<<16)
+		runFlags := make([]byte, (size+7)/8)
+		for i, c := range b.containers {
+			if _, ok := c.(*runContainer); ok {
+				runFlags[i/8] |= 1 << (i % 8)
+			}
+		}
+		header = append(header, runFlags...)
+	} else {
+		header = binary.LittleEndian.AppendUint32(header, serialCookieNoRunContainer)
+		header = binary.LittleEndian.AppendUint32(header, uint32(size))
+	}
+
+	for i, c := range b.containers {
+		header = binary.LittleEndian.AppendUint16(header, b.keys[i])
+		header = binary.LittleEndian.AppendUint16(header, uint16(c.cardinality()-1))
+	}
+
+	if !hasRun || size >= noOffsetThreshold {
+		offset := uint32(len(header) + 4*size)
+		for _, c := range b.containers {
+			header = binary.LittleEndian.AppendUint32(header, offset)
+			offset += uint32(containerSize(c))
+		}
+	}
+
+	n, err := w.Write(header)
+	total := int64(n)
+	if err != nil {
+		return total, fmt.Errorf("write header: %w", err)
+	}
+
+	for i, c := range b.containers {
+		n, err = w.Write(appendContainer(make([]byte, 0, containerSize(c)), c))
+		total += int64(n)
+		if err != nil {
+			return total, fmt.Errorf("write container[%d]: %w", i, err)
+		}
+	}
+
+	return total, nil
+}
+
+func containerSize(c container) int {
+	if v, ok := c.(*runContainer); ok {
+		return 2 + 4*len(v.runs)
+	}
+	if card := c.cardinality(); card <= arrayMaxSize {
+		return 2 * card
+	}
+	return bitmapWords * 8
+}
+
+func appendContainer(out []byte, c container) []byte {
+	if v, ok := c.(*runContainer); ok {
+		out = binary.LittleEndian.AppendUint16(out, uint16(len(v.runs)))
+		for _, r := range v.runs {
+			out = binary.LittleEndian.AppendUint16(out, r.start)
+			out = binary.LittleEndian.AppendUint16(out, r.length)
+		}
+		return out
+	}
+
+	if c.cardinality() <= arrayMaxSize {
+		for _, v := range toArray(c).values {
+			out = binary.LittleEndian.AppendUint16(out, v)
+		}
+		return out
+	}
+
+	for _, word := range toBitmap(c).words {
+		out = binary.LittleEndian.AppendUint64(out, word)
+	}
+	return out
+}
+
+func (b *Bitmap) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countReader{r: r}
+
+	var cookie uint32
+	if err := binary.Read(cr, binary.LittleEndian, &cookie); err != nil {
+		return cr.n, fmt.Errorf("read cookie: %w", err)
+	}
+
+	var (
+		size     int
+		runFlags []byte
+	)
+
+	switch {
+	case cookie&0xFFFF == serialCookie:
+		size = int(cookie>>16) + 1
+		runFlags = make([]byte, (size+7)/8)
+		if _, err := io.ReadFull(cr, runFlags); err != nil {
+			return cr.n, fmt.Errorf("read run flags: %w", err)
+		}
+	case cookie == serialCookieNoRunContainer:
+		var count uint32
+		if err := binary.Read(cr, binary.LittleEndian, &count); err != nil {
+			return cr.n, fmt.Errorf("read containers count: %w", err)
+		}
+		if count > 1<<16 {
+			return cr.n, fmt.Errorf("%w: too many containers %d", ErrInvalidFormat, count)
+		}
+		size = int(count)
+	default:
+		return cr.n, fmt.Errorf("%w: unknown cookie %d", ErrInvalidFormat, cookie)
+	}
+
+	descriptive := make([]uint16, 2*size)
+	if err := binary.Read(cr, binary.LittleEndian, descriptive); err != nil {
+		return cr.n, fmt.Errorf("read descriptive header: %w", err)
+	}
+
+	if runFlags == nil || size >= noOffsetThreshold {
+		if _, err := io.CopyN(io.Discard, cr, int64(4*size)); err != nil {
+			return cr.n, fmt.Errorf("read offset header: %w", err)
+		}
+	}
+
+	keys := make([]uint16, size)
+	containers := make([]container, size)
+
+	for i := 0; i < size; i++ {
+		keys[i] = descriptive[2*i]
+		if i > 0 && keys[i] <= keys[i-1] {
+			return cr.n, fmt.Errorf("%w: unsorted keys", ErrInvalidFormat)
+		}
+
+		card := int(descriptive[2*i+1]) + 1
+		isRun := runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0
+
+		c, err := readContainer(cr, card, isRun)
+		if err != nil {
+			return cr.n, fmt.Errorf("read container[%d]: %w", i, err)
+		}
+		containers[i] = c
+	}
+
+	b.keys, b.containers = keys, containers
+
+	return cr.n, nil
+}
+
+func readContainer(r io.Reader, card int, isRun bool) (container, error) {
+	switch {
+	case isRun:
+		var count uint16
+		if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+			return nil, err
+		}
+		data := make([]uint16, 2*int(count))
+		if err := binary.Read(r, binary.LittleEndian, data); err != nil {
+			return nil, err
+		}
+		c := &runContainer{runs: make([]interval, count)}
+		for i := range c.runs {
+			c.runs[i] = interval{start: data[2*i], length: data[2*i+1]}
+			if c.runs[i].last() > 0xFFFF || (i > 0 && int(c.runs[i].start) <= c.runs[i-1].last()) {
+				return nil, fmt.Errorf("%w: invalid run", ErrInvalidFormat)
+			}
+		}
+		if c.cardinality() != card {
+			return nil, fmt.Errorf("%w: cardinality mismatch", ErrInvalidFormat)
+		}
+		return c, nil
+
+	case card <= arrayMaxSize:
+		c := &arrayContainer{values: make([]uint16, card)}
+		if err := binary.Read(r, binary.LittleEndian, c.values); err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(c.values); i++ {
+			if c.values[i] <= c.values[i-1] {
+				return nil, fmt.Errorf("%w: unsorted values", ErrInvalidFormat)
+			}
+		}
+		return c, nil
+
+	default:
+		c := &bitmapContainer{}
+		if err := binary.Read(r, binary.LittleEndian, c.words[:]); err != nil {
+			return nil, err
+		}
+		for _, word := range c.words {
+			c.card += bits.OnesCount64(word)
+		}
+		if c.card != card {
+			return nil, fmt.Errorf("%w: cardinality mismatch", ErrInvalidFormat)
+		}
+		return c, nil
+	}
+}
+
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
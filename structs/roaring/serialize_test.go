@@ -0,0 +1,111 @@
+/*
+ *  Copyright (c) 2019-2026 Mikhail Knyazhev <markus621@yandex.com>. All rights reserved.
+ *  Use of this source code is governed by a BSD 3-Clause license that can be found in the LICENSE file.
+ */
+
+package roaring
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"go.osspkg.com/casecheck"
+)
+
+func TestUnit_Roaring_PortableFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func() *Bitmap
+		want  []byte
+	}{
+		{
+			name:  "empty",
+			setup: func() *Bitmap { return New() },
+			want:  []byte{0x3A, 0x30, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			name:  "arrays without runs",
+			setup: func() *Bitmap { return New(1, 2, 1<<16|3) },
+			want: []byte{
+				0x3A, 0x30, 0, 0, // cookie
+				2, 0, 0, 0, // containers count
+				0, 0, 1, 0, // key 0, cardinality 2
+				1, 0, 0, 0, // key 1, cardinality 1
+				24, 0, 0, 0, // offset 0
+				28, 0, 0, 0, // offset 1
+				1, 0, 2, 0, // container 0
+				3, 0, // container 1
+			},
+		},
+		{
+			name: "run container",
+			setup: func() *Bitmap {
+				b := New()
+				for i := uint32(0); i < 100; i++ {
+					b.Add(i)
+				}
+				b.RunOptimize()
+				return b
+			},
+			want: []byte{
+				0x3B, 0x30, 0, 0, // cookie with containers count - 1
+				1,           // run flags
+				0, 0, 99, 0, // key 0, cardinality 100
+				1, 0, // runs count
+				0, 0, 99, 0, // run start 0, length 100
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.setup()
+
+			got, err := b.MarshalBinary()
+			casecheck.NoError(t, err)
+			casecheck.Equal(t, tt.want, got)
+
+			restored := New(42)
+			casecheck.NoError(t, restored.UnmarshalBinary(tt.want))
+			casecheck.Equal(t, b.ToArray(), restored.ToArray())
+		})
+	}
+}
+
+func TestUnit_Roaring_RoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+
+	for _, optimize := range []bool{false, true} {
+		// more than noOffsetThreshold containers with all container kinds
+		b := New(sortedKeys(randomSet(rnd))...)
+		if optimize {
+			b.RunOptimize()
+		}
+
+		buf := bytes.NewBuffer(nil)
+		n, err := b.WriteTo(buf)
+		casecheck.NoError(t, err)
+		casecheck.Equal(t, int64(buf.Len()), n)
+
+		restored := New()
+		m, err := restored.ReadFrom(buf)
+		casecheck.NoError(t, err)
+		casecheck.Equal(t, n, m)
+		casecheck.Equal(t, b.ToArray(), restored.ToArray())
+	}
+}
+
+func TestUnit_Roaring_InvalidFormat(t *testing.T) {
+	err := New().UnmarshalBinary([]byte{1, 2, 3, 4})
+	casecheck.True(t, errors.Is(err, ErrInvalidFormat))
+
+	err = New().UnmarshalBinary([]byte{0x3A, 0x30, 0, 0, 1, 0, 0, 0})
+	casecheck.Error(t, err)
+
+	for _, values := range [][]byte{{2, 0, 1, 0}, {1, 0, 1, 0}} {
+		data := append([]byte{0x3A, 0x30, 0, 0, 1, 0, 0, 0, 0, 0, 1, 0, 16, 0, 0, 0}, values...)
+		err = New().UnmarshalBinary(data)
+		casecheck.True(t, errors.Is(err, ErrInvalidFormat), "values %v", values)
+	}
+}